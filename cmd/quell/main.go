@@ -1,6 +1,11 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Microindole/quell/internal/cli"
 	"github.com/Microindole/quell/internal/config"
 	"github.com/Microindole/quell/internal/core"
 	"github.com/Microindole/quell/internal/system"
@@ -13,9 +18,22 @@ func main() {
 	cfgManager := config.NewManager()
 	cfg, _ := cfgManager.Load() // 忽略错误使用默认值
 
+	// 全局选项写在子命令之前，例如 `quell --read-only` 或 `quell --dry-run serve --api :9257`
+	flags := flag.NewFlagSet("quell", flag.ExitOnError)
+	readOnly := flags.Bool("read-only", false, "observe only: disable every action that signals a process")
+	dryRun := flags.Bool("dry-run", false, "go through confirmations but only report what would be sent")
+	_ = flags.Parse(os.Args[1:])
+
 	// 2. 初始化 Service
 	provider := system.NewLocalProvider()
 	service := core.NewService(provider)
+	service.SetCPUMode(core.CPUMode(cfg.CPUMode))
+	switch {
+	case *readOnly:
+		service.SetMode(core.ModeReadOnly)
+	case *dryRun:
+		service.SetMode(core.ModeDryRun)
+	}
 
 	// 3. 🔥 核心修正：恢复暂停状态（带类型转换）
 	// 因为 Service 为了解耦使用了匿名结构体，这里需要手动转换一下
@@ -36,13 +54,57 @@ func main() {
 		service.RestorePausedPIDs(restoreList)
 	}
 
-	// 4. 启动 UI
+	// 4. 误杀保护名单与进程目录，配置有误时沿用内置名单
+	if err := service.SetSafeguards(core.SafeguardOptions(cfg.Safeguards)); err != nil {
+		fmt.Fprintln(os.Stderr, "quell: safeguards:", err)
+	}
+	catalog, errs := core.LoadCatalog(cfg.CatalogPaths())
+	for _, err := range errs {
+		fmt.Fprintln(os.Stderr, "quell: catalog:", err)
+	}
+	service.SetCatalog(catalog)
+
+	// 5. 进程历史文件 (~/.quell/history.ring)，打开失败时不影响其他功能
+	if h := cfg.History; !h.Disabled {
+		if store, err := core.OpenHistoryStore(h.FilePath(), h.MaxBytes(), h.RetentionDuration()); err == nil {
+			service.SetHistoryStore(store)
+		}
+	}
+
+	// 6. 审计日志 (~/.quell/audit.log)，记录每一次发出的信号
+	if a := cfg.Audit; !a.Disabled {
+		if log, err := core.OpenAuditLog(a.FilePath()); err == nil {
+			service.SetAuditLog(log)
+			defer log.Close()
+		} else {
+			fmt.Fprintln(os.Stderr, "quell: audit log:", err)
+		}
+	}
+
+	// 7. 子命令模式 (quell who-has ...)，执行完直接退出
+	if handled, err := cli.Run(flags.Args(), &cli.Env{Service: service.As(core.SurfaceCLI), Config: cfg, Out: os.Stdout, Err: os.Stderr}); handled {
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "quell:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// 8. 启动 UI，同时在后台记录历史
+	_ = service.StartRecorder(cfg.History.IntervalDuration())
+	defer service.StopRecorder()
 	model := tui.NewModel(service, cfg)
 	p := tea.NewProgram(model, tea.WithAltScreen())
 
-	// ... Run ...
+	// 控制 socket (~/.quell/run/<pid>.sock)，供 `quell ctl` 操作当前会话
+	if cfg.Control {
+		if ctl, err := tui.ListenControl(tui.ControlSocketPath(os.Getpid())); err == nil {
+			go ctl.Serve(p)
+			defer ctl.Close()
+		}
+	}
 
-	// 5. 退出保存
+	// 9. 退出保存
 	if _, err := p.Run(); err == nil {
 		finalConfig := model.GetSnapshot()
 		_ = cfgManager.Save(finalConfig)
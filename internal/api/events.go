@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Microindole/quell/internal/core"
+)
+
+// keepAlive SSE 注释行的间隔，防止代理断开空闲连接
+const keepAlive = 15 * time.Second
+
+// Event 进程生命周期事件
+type Event struct {
+	Type    string      `json:"type"` // start | exit
+	Time    time.Time   `json:"time"`
+	Process processJSON `json:"process"`
+}
+
+type procKey struct {
+	pid        int32
+	createTime int64
+}
+
+// eventHub 有订阅者时定期扫描，对比前后两次的进程集合产生事件
+type eventHub struct {
+	svc      *core.Service
+	interval time.Duration
+
+	mu      sync.Mutex
+	subs    map[chan Event]struct{}
+	running bool
+}
+
+func newEventHub(svc *core.Service, interval time.Duration) *eventHub {
+	return &eventHub{svc: svc, interval: interval, subs: make(map[chan Event]struct{})}
+}
+
+func (h *eventHub) subscribe() (chan Event, func()) {
+	ch := make(chan Event, 64)
+	h.mu.Lock()
+	h.subs[ch] = struct{}{}
+	if !h.running {
+		h.running = true
+		go h.run()
+	}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subs, ch)
+		h.mu.Unlock()
+	}
+}
+
+func (h *eventHub) run() {
+	prev := h.scan()
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		h.mu.Lock()
+		if len(h.subs) == 0 {
+			// 最后一个订阅者离开后停止扫描
+			h.running = false
+			h.mu.Unlock()
+			return
+		}
+		h.mu.Unlock()
+
+		cur := h.scan()
+		if cur == nil {
+			continue
+		}
+		now := time.Now()
+		for k, p := range cur {
+			if _, ok := prev[k]; !ok {
+				h.broadcast(Event{Type: "start", Time: now, Process: toJSON(p)})
+			}
+		}
+		for k, p := range prev {
+			if _, ok := cur[k]; !ok {
+				h.broadcast(Event{Type: "exit", Time: now, Process: toJSON(p)})
+			}
+		}
+		prev = cur
+	}
+}
+
+func (h *eventHub) scan() map[procKey]core.Process {
+	procs, err := h.svc.GetProcesses()
+	if err != nil {
+		return nil
+	}
+	m := make(map[procKey]core.Process, len(procs))
+	for _, p := range procs {
+		m[procKey{p.PID, p.CreateTime}] = p
+	}
+	return m
+}
+
+// broadcast 发给所有订阅者；消费太慢的订阅者会丢事件而不是拖住扫描
+func (h *eventHub) broadcast(ev Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// handleEvents GET /api/v1/events (server-sent events)
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming not supported"))
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ch, cancel := s.events.subscribe()
+	defer cancel()
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			_, _ = fmt.Fprint(w, ": keepalive\n\n")
+		case ev := <-ch:
+			data, _ := json.Marshal(ev)
+			_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type, data)
+		}
+		flusher.Flush()
+	}
+}
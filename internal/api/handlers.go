@@ -0,0 +1,371 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Microindole/quell/internal/core"
+)
+
+var (
+	errNotFound = errors.New("process not found")
+	errIdentity = errors.New("process identity changed (PID was reused)")
+)
+
+// processJSON API 中进程的表示
+type processJSON struct {
+	PID        int32    `json:"pid"`
+	PPID       int32    `json:"ppid"`
+	CreateTime int64    `json:"create_time"`
+	Name       string   `json:"name"`
+	User       string   `json:"user"`
+	Status     string   `json:"status"`
+	Cmdline    string   `json:"cmdline"`
+	CPU        float64  `json:"cpu_percent"`
+	RSS        uint64   `json:"rss_bytes"`
+	Ports      []int    `json:"ports"`
+	IORead     *float64 `json:"io_read_bps,omitempty"`
+	IOWrite    *float64 `json:"io_write_bps,omitempty"`
+	Nice       *int32   `json:"nice,omitempty"`
+	Priority   *int32   `json:"priority,omitempty"`
+	IOPriority string   `json:"io_priority,omitempty"`
+	Affinity   string   `json:"affinity,omitempty"`
+}
+
+func toJSON(p core.Process) processJSON {
+	j := processJSON{
+		PID: p.PID, PPID: p.PPID, CreateTime: p.CreateTime, Name: p.Name, User: p.User,
+		Status: p.Status, Cmdline: p.Cmdline, CPU: p.CpuPercent, RSS: p.MemoryUsage, Ports: p.Ports,
+	}
+	if j.Ports == nil {
+		j.Ports = []int{}
+	}
+	if p.HasIO {
+		j.IORead, j.IOWrite = &p.IOReadRate, &p.IOWriteRate
+	}
+	if p.HasPriority {
+		j.Nice, j.Priority, j.IOPriority = &p.Nice, &p.Priority, p.IOPriority.String()
+	}
+	j.Affinity = p.Affinity.String()
+	return j
+}
+
+// handleList GET /api/v1/processes?name=&user=&port=&q=&status=&sort=cpu|mem|pid&limit=
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	procs, err := s.svc.GetProcesses()
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	q := r.URL.Query()
+	name := strings.ToLower(q.Get("name"))
+	text := strings.ToLower(q.Get("q"))
+	port, _ := strconv.Atoi(q.Get("port"))
+
+	var out []core.Process
+	for _, p := range procs {
+		switch {
+		case name != "" && !strings.Contains(strings.ToLower(p.Name), name):
+			continue
+		case q.Get("user") != "" && p.User != q.Get("user"):
+			continue
+		case q.Get("status") != "" && !strings.EqualFold(p.Status, q.Get("status")):
+			continue
+		case port > 0 && !hasPort(p, port):
+			continue
+		case text != "" && !strings.Contains(strings.ToLower(p.Name+" "+p.Cmdline), text):
+			continue
+		}
+		out = append(out, p)
+	}
+
+	switch q.Get("sort") {
+	case "cpu":
+		sort.SliceStable(out, func(i, j int) bool { return out[i].CpuPercent > out[j].CpuPercent })
+	case "mem":
+		sort.SliceStable(out, func(i, j int) bool { return out[i].MemoryUsage > out[j].MemoryUsage })
+	default:
+		sort.SliceStable(out, func(i, j int) bool { return out[i].PID < out[j].PID })
+	}
+	if limit, err := strconv.Atoi(q.Get("limit")); err == nil && limit > 0 && limit < len(out) {
+		out = out[:limit]
+	}
+
+	res := make([]processJSON, len(out))
+	for i, p := range out {
+		res[i] = toJSON(p)
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func hasPort(p core.Process, port int) bool {
+	for _, pp := range p.Ports {
+		if pp == port {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup 根据路径中的 pid 找到进程；带 create_time 参数时校验身份，避免 PID 被复用后误操作
+func (s *Server) lookup(r *http.Request) (core.Process, error) {
+	pid, err := strconv.ParseInt(r.PathValue("pid"), 10, 32)
+	if err != nil {
+		return core.Process{}, fmt.Errorf("%w: invalid pid %q", errNotFound, r.PathValue("pid"))
+	}
+	procs, err := s.svc.GetProcesses()
+	if err != nil {
+		return core.Process{}, err
+	}
+	for _, p := range procs {
+		if p.PID != int32(pid) {
+			continue
+		}
+		if ct := r.URL.Query().Get("create_time"); ct != "" && ct != strconv.FormatInt(p.CreateTime, 10) {
+			return core.Process{}, errIdentity
+		}
+		return p, nil
+	}
+	return core.Process{}, errNotFound
+}
+
+type detailJSON struct {
+	processJSON
+	Threads     int               `json:"threads"`
+	OpenFDs     *int              `json:"open_fds,omitempty"`
+	Memory      *memoryJSON       `json:"memory,omitempty"`
+	Connections []core.Connection `json:"connections"`
+}
+
+type memoryJSON struct {
+	PSS    uint64 `json:"pss_bytes"`
+	USS    uint64 `json:"uss_bytes"`
+	Swap   uint64 `json:"swap_bytes"`
+	Shared uint64 `json:"shared_bytes"`
+}
+
+// handleDetail GET /api/v1/processes/{pid}
+func (s *Server) handleDetail(w http.ResponseWriter, r *http.Request) {
+	p, err := s.lookup(r)
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	d := detailJSON{processJSON: toJSON(p), Connections: []core.Connection{}}
+	if rc, err := s.svc.GetResourceCounts(p.PID); err == nil {
+		d.Threads = rc.Threads
+		if rc.HasFDs {
+			d.OpenFDs = &rc.FDs
+		}
+	}
+	if md, err := s.svc.GetMemoryDetail(p.PID); err == nil {
+		d.Memory = &memoryJSON{PSS: md.PSS, USS: md.USS, Swap: md.Swap, Shared: md.Shared}
+	}
+	if conns, err := s.svc.GetConnections(p.PID); err == nil && conns != nil {
+		d.Connections = conns
+	}
+	writeJSON(w, http.StatusOK, d)
+}
+
+// actionResult 一次操作的结果
+type actionResult struct {
+	PID     int32  `json:"pid"`
+	Name    string `json:"name"`
+	Action  string `json:"action"`
+	OK      bool   `json:"ok"`
+	DryRun  bool   `json:"dry_run,omitempty"`
+	Message string `json:"message,omitempty"` // 演练模式下描述将要执行的操作
+	Error   string `json:"error,omitempty"`
+}
+
+func result(p core.Process, action string, err error) actionResult {
+	res := actionResult{PID: p.PID, Name: p.Name, Action: action, OK: err == nil}
+	switch {
+	case errors.Is(err, core.ErrDryRun):
+		res.OK, res.DryRun, res.Message = true, true, err.Error()
+	case err != nil:
+		res.Error = err.Error()
+	}
+	return res
+}
+
+// act 查找进程并执行 op，返回单个结果；op 应使用传入的 svc，请求中的确认只对它生效
+func (s *Server) act(w http.ResponseWriter, r *http.Request, action string, op func(svc *core.Service, p core.Process) error) {
+	p, err := s.lookup(r)
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	// 演练模式下操作被拦截也算成功，结果中带有 dry_run 标记
+	status := http.StatusOK
+	if err := op(s.confirmed(r, p), p); err != nil {
+		if !errors.Is(err, core.ErrDryRun) {
+			status = statusFor(err)
+		}
+		writeJSON(w, status, result(p, action, err))
+		return
+	}
+	writeJSON(w, status, result(p, action, nil))
+}
+
+// confirmed 受保护的进程需要在查询参数中带上 confirm=<进程名>，相当于 TUI 中的输入确认
+// 确认只对本次请求生效，不会留下对其他客户端或 TUI 也有效的授权
+func (s *Server) confirmed(r *http.Request, p core.Process) *core.Service {
+	if name := r.URL.Query().Get("confirm"); name != "" && name == p.Name {
+		return s.svc.Confirmed(p)
+	}
+	return s.svc
+}
+
+// decodeBody 解析可选的 JSON 请求体，空请求体视为默认值
+func decodeBody(r *http.Request, v interface{}) error {
+	if r.ContentLength == 0 {
+		return nil
+	}
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+// handleSignal POST /api/v1/processes/{pid}/signal  {"signal": "HUP"}
+func (s *Server) handleSignal(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Signal string `json:"signal"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if body.Signal == "" {
+		body.Signal = r.URL.Query().Get("signal")
+	}
+	sig, err := core.ParseSignal(body.Signal)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	s.act(w, r, core.SignalName(sig), func(svc *core.Service, p core.Process) error { return svc.Signal(p.PID, sig) })
+}
+
+// handleKill POST /api/v1/processes/{pid}/kill  {"force": true}
+func (s *Server) handleKill(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Force bool `json:"force"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	s.act(w, r, "kill", func(svc *core.Service, p core.Process) error { return svc.Kill(p.PID, body.Force) })
+}
+
+func (s *Server) handleSuspend(w http.ResponseWriter, r *http.Request) {
+	s.act(w, r, "suspend", func(svc *core.Service, p core.Process) error { return svc.Suspend(p.PID) })
+}
+
+func (s *Server) handleResume(w http.ResponseWriter, r *http.Request) {
+	s.act(w, r, "resume", func(svc *core.Service, p core.Process) error { return svc.Resume(p.PID) })
+}
+
+// handlePriority POST /api/v1/processes/{pid}/priority  {"nice": 10, "io": "idle"}
+// 两项都可以省略，先改 nice 再改 I/O 优先级
+func (s *Server) handlePriority(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Nice *int   `json:"nice"`
+		IO   string `json:"io"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if body.Nice == nil && body.IO == "" {
+		writeError(w, http.StatusBadRequest, errors.New(`nothing to change (expected "nice" and/or "io")`))
+		return
+	}
+	var actions []string
+	if body.Nice != nil {
+		if *body.Nice < core.MinNice || *body.Nice > core.MaxNice {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("nice must be between %d and %d", core.MinNice, core.MaxNice))
+			return
+		}
+		actions = append(actions, "renice")
+	}
+	var prio core.IOPriority
+	if body.IO != "" {
+		var err error
+		if prio, err = core.ParseIOPriority(body.IO); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		actions = append(actions, "ionice")
+	}
+	s.act(w, r, strings.Join(actions, "+"), func(svc *core.Service, p core.Process) error {
+		if body.Nice != nil {
+			if err := svc.Renice(p.PID, *body.Nice); err != nil {
+				return err
+			}
+		}
+		if body.IO != "" {
+			return svc.SetIOPriority(p.PID, prio)
+		}
+		return nil
+	})
+}
+
+// handleAffinity POST /api/v1/processes/{pid}/affinity  {"cpus": "0-3,8", "threads": true}
+func (s *Server) handleAffinity(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		CPUs    string `json:"cpus"`
+		Threads bool   `json:"threads"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	cpus, err := core.ParseCPUSet(body.CPUs)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	s.act(w, r, "affinity", func(svc *core.Service, p core.Process) error { return svc.SetAffinity(p.PID, cpus, body.Threads) })
+}
+
+// handleFreePort POST /api/v1/ports/{port}/free  {"force": false}
+// 终止所有监听该端口的进程
+func (s *Server) handleFreePort(w http.ResponseWriter, r *http.Request) {
+	port, err := strconv.Atoi(r.PathValue("port"))
+	if err != nil || port <= 0 || port > 65535 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid port %q", r.PathValue("port")))
+		return
+	}
+	var body struct {
+		Force bool `json:"force"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	procs, err := s.svc.GetProcesses()
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	results := []actionResult{}
+	for _, p := range procs {
+		if hasPort(p, port) {
+			results = append(results, result(p, "kill", s.confirmed(r, p).Kill(p.PID, body.Force)))
+		}
+	}
+	if len(results) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no process is listening on port %d", port))
+		return
+	}
+	writeJSON(w, http.StatusOK, results)
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Microindole/quell/internal/core"
+)
+
+// Options 控制 API 的访问方式
+type Options struct {
+	Token         string        // Bearer token，为空时不校验 (只应用于权限为 0600 的 Unix socket)
+	ReadOnly      bool          // 拒绝所有会改变系统状态的请求
+	EventInterval time.Duration // 生命周期事件的扫描间隔，默认 2 秒
+}
+
+// ErrReadOnly API 以只读方式启动
+var ErrReadOnly = errors.New("API is read-only")
+
+// Server 基于 core.Service 的 HTTP/JSON 控制接口
+type Server struct {
+	svc    *core.Service
+	opts   Options
+	mux    *http.ServeMux
+	events *eventHub
+}
+
+func NewServer(svc *core.Service, opts Options) *Server {
+	if opts.EventInterval <= 0 {
+		opts.EventInterval = 2 * time.Second
+	}
+	s := &Server{
+		svc:    svc,
+		opts:   opts,
+		mux:    http.NewServeMux(),
+		events: newEventHub(svc, opts.EventInterval),
+	}
+
+	s.mux.HandleFunc("GET /api/v1/processes", s.handleList)
+	s.mux.HandleFunc("GET /api/v1/processes/{pid}", s.handleDetail)
+	s.mux.HandleFunc("POST /api/v1/processes/{pid}/signal", s.mutating(s.handleSignal))
+	s.mux.HandleFunc("POST /api/v1/processes/{pid}/kill", s.mutating(s.handleKill))
+	s.mux.HandleFunc("POST /api/v1/processes/{pid}/suspend", s.mutating(s.handleSuspend))
+	s.mux.HandleFunc("POST /api/v1/processes/{pid}/resume", s.mutating(s.handleResume))
+	s.mux.HandleFunc("POST /api/v1/processes/{pid}/priority", s.mutating(s.handlePriority))
+	s.mux.HandleFunc("POST /api/v1/processes/{pid}/affinity", s.mutating(s.handleAffinity))
+	s.mux.HandleFunc("POST /api/v1/ports/{port}/free", s.mutating(s.handleFreePort))
+	s.mux.HandleFunc("GET /api/v1/events", s.handleEvents)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Token != "" && !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="quell"`)
+		writeError(w, http.StatusUnauthorized, errors.New("missing or invalid token"))
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) == 1
+}
+
+// mutating 包装会改变系统状态的接口，只读模式下直接拒绝
+func (s *Server) mutating(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.opts.ReadOnly {
+			writeError(w, http.StatusForbidden, ErrReadOnly)
+			return
+		}
+		h(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// statusFor 把 core 层的错误映射为 HTTP 状态码
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, errNotFound), errors.Is(err, syscall.ESRCH):
+		return http.StatusNotFound
+	case errors.Is(err, errIdentity):
+		return http.StatusConflict
+	case errors.Is(err, core.ErrReadOnly), errors.Is(err, core.ErrProtected):
+		return http.StatusForbidden
+	case errors.Is(err, core.ErrNeedsConfirm):
+		return http.StatusPreconditionRequired
+	case errors.Is(err, core.ErrPermissionDenied), errors.Is(err, syscall.EPERM), errors.Is(err, syscall.EACCES):
+		return http.StatusForbidden
+	case errors.Is(err, core.ErrNotSupported):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Microindole/quell/internal/config"
+	"github.com/Microindole/quell/internal/core"
+)
+
+// Env 子命令运行所需的依赖，由 main 注入
+type Env struct {
+	Service *core.Service
+	Config  *config.Config
+	Out     io.Writer
+	Err     io.Writer
+}
+
+// Command 描述一个子命令，例如 `quell who-has /mnt/data`
+type Command struct {
+	Name    string
+	Usage   string
+	Summary string
+	Run     func(args []string, env *Env) error
+}
+
+// Registry 全局子命令注册表
+var Registry = make(map[string]*Command)
+
+// Run 尝试把 args 当作子命令执行
+// 返回 false 表示 args 不是子命令 (例如为空或是 TUI 的 flag)，调用方应启动 TUI
+func Run(args []string, env *Env) (bool, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return false, nil
+	}
+	if len(Registry) == 0 {
+		RegisterAll(Registry)
+	}
+
+	name := args[0]
+	if name == "help" {
+		PrintUsage(env.Out)
+		return true, nil
+	}
+	cmd, ok := Registry[name]
+	if !ok {
+		return true, fmt.Errorf("unknown command %q (see `quell help`)", name)
+	}
+	return true, cmd.Run(args[1:], env)
+}
+
+// PrintUsage 打印所有子命令
+func PrintUsage(w io.Writer) {
+	var names []string
+	for name := range Registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	_, _ = fmt.Fprintln(w, "Usage: quell [command] [args]")
+	_, _ = fmt.Fprintln(w, "\nWithout a command, quell starts the interactive TUI.\n\nCommands:")
+	for _, name := range names {
+		c := Registry[name]
+		_, _ = fmt.Fprintf(w, "  %-28s %s\n", c.Usage, c.Summary)
+	}
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Microindole/quell/internal/config"
+	"github.com/Microindole/quell/internal/tui"
+)
+
+// Ctl 实现 `quell ctl [--pid N] <command> [args...]`
+// 把命令发送给正在运行的 TUI (需要在配置中开启 control_socket)，效果与在命令模式中输入相同
+func Ctl(args []string, env *Env) error {
+	fs := flag.NewFlagSet("ctl", flag.ContinueOnError)
+	fs.SetOutput(env.Err)
+	pid := fs.Int("pid", 0, "target TUI process (default: the only running session)")
+	list := fs.Bool("list", false, "list running sessions")
+	timeout := fs.Duration("timeout", 15*time.Second, "how long to wait for the result")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	sessions := liveSessions()
+	if *list {
+		if len(sessions) == 0 {
+			_, _ = fmt.Fprintln(env.Out, "No running quell session with a control socket")
+		}
+		for _, s := range sessions {
+			_, _ = fmt.Fprintf(env.Out, "%d\t%s\n", s, tui.ControlSocketPath(s))
+		}
+		return nil
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: quell ctl [--pid N] <command> [args...] (e.g. quell ctl filter node)")
+	}
+
+	target := *pid
+	if target == 0 {
+		switch len(sessions) {
+		case 0:
+			return fmt.Errorf("no running quell session found (set \"control_socket\": true in %s)", filepath.Join(config.Dir(), "config.json"))
+		case 1:
+			target = sessions[0]
+		default:
+			var pids []string
+			for _, s := range sessions {
+				pids = append(pids, strconv.Itoa(s))
+			}
+			return fmt.Errorf("several sessions are running (%s), choose one with --pid", strings.Join(pids, ", "))
+		}
+	}
+
+	conn, err := net.DialTimeout("unix", tui.ControlSocketPath(target), time.Second)
+	if err != nil {
+		return fmt.Errorf("cannot reach session %d: %w", target, err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(*timeout))
+
+	if _, err := fmt.Fprintln(conn, strings.Join(fs.Args(), " ")); err != nil {
+		return err
+	}
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return fmt.Errorf("no reply from session %d: %w", target, err)
+	}
+	var reply tui.ControlReply
+	if err := json.Unmarshal(line, &reply); err != nil {
+		return err
+	}
+	if !reply.OK {
+		return errors.New(reply.Error)
+	}
+	_, _ = fmt.Fprintln(env.Out, reply.Message)
+	return nil
+}
+
+// liveSessions 列出 ~/.quell/run 下可以连接的会话，顺便清理崩溃后留下的 socket
+func liveSessions() []int {
+	entries, err := os.ReadDir(config.RunDir())
+	if err != nil {
+		return nil
+	}
+	var pids []int
+	for _, e := range entries {
+		pid, err := strconv.Atoi(strings.TrimSuffix(e.Name(), ".sock"))
+		if err != nil || !strings.HasSuffix(e.Name(), ".sock") {
+			continue
+		}
+		path := tui.ControlSocketPath(pid)
+		conn, err := net.DialTimeout("unix", path, 200*time.Millisecond)
+		if err != nil {
+			if errors.Is(err, syscall.ECONNREFUSED) {
+				_ = os.Remove(path)
+			}
+			continue
+		}
+		conn.Close()
+		pids = append(pids, pid)
+	}
+	sort.Ints(pids)
+	return pids
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Microindole/quell/internal/export"
+)
+
+// Export 实现 `quell export [--format json|csv|md] [--columns a,b] [--tree] [--out file]`
+func Export(args []string, env *Env) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.SetOutput(env.Err)
+	format := fs.String("format", "", "json, csv or md (default: from --out extension, else json)")
+	cols := fs.String("columns", "", "comma-separated columns or \"all\" ("+strings.Join(export.ColumnNames(), ",")+")")
+	tree := fs.Bool("tree", false, "nest (JSON) or indent (CSV/Markdown) children under their parent")
+	out := fs.String("out", "", "write to file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts := export.Options{Tree: *tree}
+	var err error
+	if opts.Columns, err = export.ParseColumns(*cols); err != nil {
+		return err
+	}
+	switch {
+	case *format != "":
+		if opts.Format, err = export.ParseFormat(*format); err != nil {
+			return err
+		}
+	case *out != "":
+		opts.Format = export.FormatFromPath(*out)
+	default:
+		opts.Format = export.FormatJSON
+	}
+
+	// 第一次扫描只能得到生命周期内的平均 CPU，稍等再扫一次得到当前值
+	if _, err := env.Service.GetProcesses(); err != nil {
+		return err
+	}
+	time.Sleep(time.Second)
+	procs, err := env.Service.GetProcesses()
+	if err != nil {
+		return err
+	}
+	if export.NeedsMemoryDetail(opts.Columns) {
+		env.Service.FillMemoryDetail(procs, nil)
+	}
+	sort.Slice(procs, func(i, j int) bool { return procs[i].PID < procs[j].PID })
+
+	if *out == "" {
+		return export.Write(env.Out, procs, opts)
+	}
+	if err := export.WriteFile(*out, procs, opts); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(env.Err, "exported %d processes to %s\n", len(procs), *out)
+	return nil
+}
+
+// Diff 实现 `quell diff [--cpu 20] [--rss 50] before.json after.json`
+func Diff(args []string, env *Env) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.SetOutput(env.Err)
+	th := export.DefaultThresholds
+	fs.Float64Var(&th.CPU, "cpu", th.CPU, "report CPU changes of at least this many percentage points")
+	rssMB := fs.Uint64("rss", th.RSSBytes>>20, "report RSS changes of at least this many MB")
+	fs.Float64Var(&th.RSSRatio, "rss-ratio", th.RSSRatio, "and at least this relative change (0.5 = 50%)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: quell diff [--cpu 20] [--rss 50] before.json after.json")
+	}
+	th.RSSBytes = *rssMB << 20
+
+	a, err := export.Load(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	b, err := export.Load(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(env.Out, "%s (%s) -> %s (%s)\n", fs.Arg(0), a.Time.Format("2006-01-02 15:04:05"),
+		fs.Arg(1), b.Time.Format("2006-01-02 15:04:05"))
+	return export.Diff(a, b, th).WriteText(env.Out)
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Microindole/quell/internal/core"
+)
+
+// History 实现 `quell history [--since 1h] [--format table|csv|json] <pid|name>`
+func History(args []string, env *Env) error {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	fs.SetOutput(env.Err)
+	since := fs.Duration("since", time.Hour, "how far back to look")
+	format := fs.String("format", "table", "output format: table, csv or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: quell history [--since 1h] [--format table|csv|json] <pid|name>")
+	}
+
+	match := historyMatcher(fs.Arg(0))
+	samples, err := env.Service.History(time.Now().Add(-*since), match)
+	if err != nil {
+		return err
+	}
+	if len(samples) == 0 {
+		_, _ = fmt.Fprintf(env.Err, "no history for %q in the last %s\n", fs.Arg(0), *since)
+		return nil
+	}
+
+	switch *format {
+	case "table":
+		return writeHistoryTable(env, samples)
+	case "csv":
+		return writeHistoryCSV(env, samples)
+	case "json":
+		enc := json.NewEncoder(env.Out)
+		for _, s := range samples {
+			if err := enc.Encode(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q (table, csv or json)", *format)
+	}
+}
+
+// historyMatcher 数字按 PID 匹配，否则按进程名匹配 (历史文件中的进程名最多 16 字节)
+func historyMatcher(target string) func(core.HistorySample) bool {
+	if pid, err := strconv.ParseInt(target, 10, 32); err == nil {
+		return func(s core.HistorySample) bool { return s.PID == int32(pid) }
+	}
+	if len(target) > 16 {
+		target = target[:16]
+	}
+	return func(s core.HistorySample) bool { return s.Name == target }
+}
+
+func historyPorts(s core.HistorySample) string {
+	var ps []string
+	for _, p := range s.Ports {
+		ps = append(ps, strconv.Itoa(p))
+	}
+	if s.PortCount > len(s.Ports) {
+		ps = append(ps, fmt.Sprintf("+%d", s.PortCount-len(s.Ports)))
+	}
+	return strings.Join(ps, ",")
+}
+
+func historyIO(s core.HistorySample, rate float64) string {
+	if !s.HasIO {
+		return "n/a"
+	}
+	return core.FormatBytes(uint64(rate)) + "/s"
+}
+
+func writeHistoryTable(env *Env, samples []core.HistorySample) error {
+	tw := tabwriter.NewWriter(env.Out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "TIME\tPID\tNAME\tCPU%\tRSS\tREAD\tWRITE\tPORTS")
+	for _, s := range samples {
+		_, _ = fmt.Fprintf(tw, "%s\t%d\t%s\t%.1f\t%s\t%s\t%s\t%s\n",
+			s.Time.Format("2006-01-02 15:04:05"), s.PID, s.Name, s.CPU, core.FormatBytes(s.RSS),
+			historyIO(s, s.IORead), historyIO(s, s.IOWrite), historyPorts(s))
+	}
+	return tw.Flush()
+}
+
+func writeHistoryCSV(env *Env, samples []core.HistorySample) error {
+	w := csv.NewWriter(env.Out)
+	_ = w.Write([]string{"time", "pid", "create_time", "name", "cpu_percent", "rss_bytes", "io_read_bps", "io_write_bps", "ports"})
+	for _, s := range samples {
+		read, write := "", ""
+		if s.HasIO {
+			read = strconv.FormatFloat(s.IORead, 'f', 0, 64)
+			write = strconv.FormatFloat(s.IOWrite, 'f', 0, 64)
+		}
+		_ = w.Write([]string{
+			s.Time.Format(time.RFC3339),
+			strconv.Itoa(int(s.PID)),
+			strconv.FormatInt(s.CreateTime, 10),
+			s.Name,
+			strconv.FormatFloat(s.CPU, 'f', 1, 64),
+			strconv.FormatUint(s.RSS, 10),
+			read, write,
+			historyPorts(s),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
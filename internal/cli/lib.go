@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// Lib 实现 `quell lib [--deleted] [name]`
+func Lib(args []string, env *Env) error {
+	fs := flag.NewFlagSet("lib", flag.ContinueOnError)
+	fs.SetOutput(env.Err)
+	deletedOnly := fs.Bool("deleted", false, "only report libraries that were deleted or replaced on disk")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	name := strings.Join(fs.Args(), " ")
+	if name == "" && !*deletedOnly {
+		return fmt.Errorf("usage: quell lib [--deleted] [name]")
+	}
+
+	users, skipped, err := env.Service.FindLibraryUsers(name, *deletedOnly)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(env.Out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "PID\tNAME\tUSER\tLIBRARY")
+	for _, u := range users {
+		for _, m := range u.Libraries {
+			lib := m.Path
+			if m.Deleted {
+				lib += " (deleted)"
+			}
+			_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", u.Process.PID, u.Process.Name, u.Process.User, lib)
+		}
+	}
+	_ = tw.Flush()
+
+	if skipped > 0 {
+		_, _ = fmt.Fprintf(env.Err, "warning: %d processes could not be inspected (permission denied), try sudo\n", skipped)
+	}
+	return nil
+}
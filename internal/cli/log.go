@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Microindole/quell/internal/core"
+)
+
+// Log 实现 `quell log [--since 1h] [--pid N] [--name x] [--surface api] [--action kill] [--failed] [--format table|json]`
+// 读取审计日志，最新的操作在最前面
+func Log(args []string, env *Env) error {
+	fs := flag.NewFlagSet("log", flag.ContinueOnError)
+	fs.SetOutput(env.Err)
+	since := fs.String("since", "", "only entries newer than this (30m, 2h or 2006-01-02)")
+	pid := fs.Int("pid", 0, "only this PID")
+	name := fs.String("name", "", "process name or cmdline contains")
+	surface := fs.String("surface", "", "only actions from key, command, ctl, cli or api")
+	action := fs.String("action", "", "only kill, signal, suspend or resume")
+	failed := fs.Bool("failed", false, "only actions that did not take effect")
+	limit := fs.Int("n", 0, "show at most N entries (0 = all)")
+	format := fs.String("format", "table", "output format: table or json")
+	file := fs.String("file", env.Config.Audit.FilePath(), "audit log to read")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("usage: quell log [flags] [name]")
+	}
+
+	filter := core.AuditFilter{
+		PID:     int32(*pid),
+		Name:    *name,
+		Surface: core.Surface(*surface),
+		Action:  *action,
+		Failed:  *failed,
+	}
+	if fs.NArg() == 1 {
+		filter.Name = fs.Arg(0)
+	}
+	if *since != "" {
+		t, err := core.ParseSince(*since, time.Now())
+		if err != nil {
+			return err
+		}
+		filter.Since = t
+	}
+
+	entries, err := core.ReadAuditLog(*file, filter)
+	if err != nil {
+		return err
+	}
+	if *limit > 0 && len(entries) > *limit {
+		entries = entries[:*limit]
+	}
+
+	switch *format {
+	case "table":
+		if len(entries) == 0 {
+			_, _ = fmt.Fprintf(env.Err, "no matching entries in %s\n", *file)
+			return nil
+		}
+		return writeAuditTable(env, entries)
+	case "json":
+		enc := json.NewEncoder(env.Out)
+		for _, e := range entries {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q (table or json)", *format)
+	}
+}
+
+func writeAuditTable(env *Env, entries []core.AuditEntry) error {
+	tw := tabwriter.NewWriter(env.Out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "TIME\tUSER\tVIA\tACTION\tPID\tNAME\tRESULT")
+	for _, e := range entries {
+		user := e.User
+		if e.SudoUser != "" {
+			user += "(" + e.SudoUser + ")"
+		}
+		what := strings.TrimSpace(strings.Join([]string{e.Action, e.Signal, e.Detail}, " "))
+		result := e.Result
+		if e.Error != "" {
+			result += ": " + e.Error
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			e.Time.Local().Format("2006-01-02 15:04:05"), user, e.Surface, what, e.PID, e.Name, result)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Microindole/quell/internal/core"
+	"github.com/Microindole/quell/internal/snapshot"
+	"github.com/Microindole/quell/internal/tui"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Record 实现 `quell record --out file [--interval 2s] [--duration 10m]`
+func Record(args []string, env *Env) error {
+	fs := flag.NewFlagSet("record", flag.ContinueOnError)
+	fs.SetOutput(env.Err)
+	out := fs.String("out", "", "recording file to write (gzip-compressed JSON lines)")
+	interval := fs.Duration("interval", 2*time.Second, "time between snapshots")
+	duration := fs.Duration("duration", 0, "stop after this long (default: until Ctrl+C)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *out == "" || fs.NArg() != 0 {
+		return fmt.Errorf("usage: quell record --out file [--interval 2s] [--duration 10m]")
+	}
+	if *interval < time.Second {
+		*interval = time.Second
+	}
+
+	w, err := snapshot.Create(*out)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if *duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *duration)
+		defer cancel()
+	}
+
+	_, _ = fmt.Fprintf(env.Err, "recording to %s every %s, press Ctrl+C to stop\n", *out, *interval)
+	frames := 0
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		fr, err := snapshot.Capture(env.Service)
+		if err == nil {
+			if err := w.Write(fr); err != nil {
+				_ = w.Close()
+				return err
+			}
+			frames++
+		}
+		select {
+		case <-ctx.Done():
+			_, _ = fmt.Fprintf(env.Err, "recorded %d frames to %s\n", frames, *out)
+			return w.Close()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Replay 实现 `quell replay file`：用录制文件驱动正常的 TUI
+func Replay(args []string, env *Env) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	fs.SetOutput(env.Err)
+	speed := fs.Float64("speed", 1, "initial playback speed")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: quell replay [--speed 1] file")
+	}
+
+	frames, err := snapshot.Load(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	rp := snapshot.NewReplayProvider(frames)
+	if *speed > 0 {
+		rp.ScaleSpeed(*speed)
+	}
+
+	// 回放不应改写用户配置，使用一份拷贝
+	cfg := *env.Config
+	model := tui.NewModel(core.NewService(rp), &cfg)
+	model.SetReplay(rp)
+	_, err = tea.NewProgram(model, tea.WithAltScreen()).Run()
+	return err
+}
@@ -0,0 +1,65 @@
+package cli
+
+// RegisterAll 将所有子命令注入到提供的注册表中
+func RegisterAll(registry map[string]*Command) {
+	registry["who-has"] = &Command{
+		Name:    "who-has",
+		Usage:   "who-has <path>",
+		Summary: "List processes holding a file, directory or mountpoint",
+		Run:     WhoHas,
+	}
+	registry["lib"] = &Command{
+		Name:    "lib",
+		Usage:   "lib [--deleted] [name]",
+		Summary: "List processes mapping a shared library (or any deleted one)",
+		Run:     Lib,
+	}
+	registry["history"] = &Command{
+		Name:    "history",
+		Usage:   "history [--since 1h] <pid|name>",
+		Summary: "Print or export recorded CPU, memory, I/O and port history",
+		Run:     History,
+	}
+	registry["record"] = &Command{
+		Name:    "record",
+		Usage:   "record --out file [--interval 2s]",
+		Summary: "Record process snapshots to a compressed file",
+		Run:     Record,
+	}
+	registry["replay"] = &Command{
+		Name:    "replay",
+		Usage:   "replay [--speed 1] file",
+		Summary: "Play a recording back in the TUI",
+		Run:     Replay,
+	}
+	registry["export"] = &Command{
+		Name:    "export",
+		Usage:   "export [--format json|csv|md] [--out file]",
+		Summary: "Export the process list with ports and selected columns",
+		Run:     Export,
+	}
+	registry["diff"] = &Command{
+		Name:    "diff",
+		Usage:   "diff before.json after.json",
+		Summary: "Compare two JSON exports: new, gone and changed processes",
+		Run:     Diff,
+	}
+	registry["serve"] = &Command{
+		Name:    "serve",
+		Usage:   "serve [--metrics :9256] [--api :9257]",
+		Summary: "Serve Prometheus metrics and/or the HTTP/JSON control API",
+		Run:     Serve,
+	}
+	registry["ctl"] = &Command{
+		Name:    "ctl",
+		Usage:   "ctl [--pid N] <command> [args...]",
+		Summary: "Send a command (filter, select, kill, export...) to a running TUI",
+		Run:     Ctl,
+	}
+	registry["log"] = &Command{
+		Name:    "log",
+		Usage:   "log [--since 1h] [--pid N] [--surface api] [--failed] [name]",
+		Summary: "Show the audit log of signals sent by quell",
+		Run:     Log,
+	}
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Microindole/quell/internal/api"
+	"github.com/Microindole/quell/internal/core"
+	"github.com/Microindole/quell/internal/metrics"
+)
+
+// Serve 实现 `quell serve [--metrics :9256] [--api localhost:9257|unix:/path] [--read-only]`
+func Serve(args []string, env *Env) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(env.Err)
+	metricsAddr := fs.String("metrics", "", "listen address for the Prometheus exporter, e.g. :9256")
+	apiAddr := fs.String("api", "", "listen address for the HTTP/JSON API: host:port (host defaults to 127.0.0.1) or unix:/path/to.sock")
+	token := fs.String("token", "", "API bearer token (default: $QUELL_API_TOKEN, or a random one for TCP)")
+	readOnly := fs.Bool("read-only", false, "reject API requests that signal, suspend or kill processes")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *metricsAddr == "" && *apiAddr == "" {
+		return fmt.Errorf("usage: quell serve [--metrics :9256] [--api localhost:9257 | --api unix:/path] [--read-only]")
+	}
+
+	type endpoint struct {
+		ln      net.Listener
+		handler http.Handler
+	}
+	var endpoints []endpoint
+
+	if *metricsAddr != "" {
+		exp, err := metrics.NewExporter(env.Service, env.Config.Metrics)
+		if err != nil {
+			return err
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", exp)
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/" {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = fmt.Fprintln(w, "quell exporter: metrics are at /metrics")
+		})
+		ln, err := net.Listen("tcp", *metricsAddr)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(env.Err, "serving metrics on http://%s/metrics\n", ln.Addr())
+		endpoints = append(endpoints, endpoint{ln, mux})
+	}
+
+	if *apiAddr != "" {
+		ln, unix, err := listenAPI(*apiAddr)
+		if err != nil {
+			return err
+		}
+		// `quell --read-only serve` 同样让 API 只读
+		opts := api.Options{Token: *token, ReadOnly: *readOnly || env.Service.Mode() == core.ModeReadOnly}
+		if opts.Token == "" {
+			opts.Token = os.Getenv("QUELL_API_TOKEN")
+		}
+		// TCP 端口同机的其他用户也能连上，必须有 token；Unix socket 依靠文件权限
+		if opts.Token == "" && !unix {
+			if opts.Token, err = randomToken(); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(env.Err, "API token (pass as \"Authorization: Bearer <token>\"): %s\n", opts.Token)
+		}
+		mode := ""
+		if opts.ReadOnly {
+			mode = " (read-only)"
+		} else if env.Service.Mode() == core.ModeDryRun {
+			mode = " (dry-run)"
+		}
+		_, _ = fmt.Fprintf(env.Err, "serving API on %s%s\n", describeListener(ln, unix), mode)
+		endpoints = append(endpoints, endpoint{ln, api.NewServer(env.Service.As(core.SurfaceAPI), opts)})
+	}
+
+	// 先扫描一次，让第一次请求的 CPU 就是基于差值计算的
+	_, _ = env.Service.GetProcesses()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errc := make(chan error, len(endpoints))
+	var servers []*http.Server
+	for _, ep := range endpoints {
+		srv := &http.Server{Handler: ep.handler, ReadHeaderTimeout: 5 * time.Second}
+		servers = append(servers, srv)
+		go func(ln net.Listener) { errc <- srv.Serve(ln) }(ep.ln)
+	}
+
+	var err error
+	select {
+	case <-ctx.Done():
+	case err = <-errc:
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, srv := range servers {
+		_ = srv.Shutdown(shutdownCtx)
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// listenAPI 解析 API 监听地址：unix:/path 为 Unix socket (权限 0600)，
+// host:port 中省略 host 时只监听 127.0.0.1
+func listenAPI(addr string) (net.Listener, bool, error) {
+	if path, ok := strings.CutPrefix(addr, "unix:"); ok {
+		// 清理上次异常退出留下的 socket 文件
+		if fi, err := os.Lstat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+			_ = os.Remove(path)
+		}
+		ln, err := net.Listen("unix", path)
+		if err != nil {
+			return nil, true, err
+		}
+		if err := os.Chmod(path, 0600); err != nil {
+			_ = ln.Close()
+			return nil, true, err
+		}
+		return ln, true, nil
+	}
+	if strings.HasPrefix(addr, ":") {
+		addr = "127.0.0.1" + addr
+	}
+	ln, err := net.Listen("tcp", addr)
+	return ln, false, err
+}
+
+func describeListener(ln net.Listener, unix bool) string {
+	if unix {
+		return "unix:" + ln.Addr().String()
+	}
+	return "http://" + ln.Addr().String()
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Microindole/quell/internal/core"
+)
+
+// WhoHas 实现 `quell who-has <path>`，类似 fuser -v / lsof +D
+func WhoHas(args []string, env *Env) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: quell who-has <path>")
+	}
+	path := strings.Join(args, " ")
+
+	holders, skipped, err := env.Service.WhoHas(path)
+	if err != nil {
+		return err
+	}
+
+	if len(holders) == 0 {
+		_, _ = fmt.Fprintf(env.Out, "No process is holding %s\n", path)
+	} else {
+		tw := tabwriter.NewWriter(env.Out, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "PID\tNAME\tUSER\tHOW\tPATH")
+		for _, h := range holders {
+			for _, r := range h.Refs {
+				how := r.Kind
+				if r.Kind == core.RefFd {
+					how = fmt.Sprintf("fd %d", r.Fd)
+				}
+				_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", h.Process.PID, h.Process.Name, h.Process.User, how, r.Path)
+			}
+		}
+		_ = tw.Flush()
+	}
+
+	if skipped > 0 {
+		_, _ = fmt.Fprintf(env.Err, "warning: %d processes could not be inspected (permission denied), try sudo\n", skipped)
+	}
+	return nil
+}
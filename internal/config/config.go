@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 )
 
 type PausedProcess struct {
@@ -17,6 +18,128 @@ type Config struct {
 	SortIndex   int             `json:"sort_index"` // 排序方式索引
 	TreeMode    bool            `json:"tree_mode"`  // 是否开启树状图
 	PausedProcs []PausedProcess `json:"paused_procs"`
+	CPUMode     string          `json:"cpu_mode,omitempty"` // irix (默认，单核 100%) 或 solaris (整机 100%)
+	HeaderFold  bool            `json:"header_collapsed"`   // 列表页顶部的系统摘要是否折叠
+	History     HistoryConfig   `json:"history"`
+	Metrics     MetricsConfig   `json:"metrics"`
+	Control     bool            `json:"control_socket,omitempty"` // 为每个 TUI 会话开启控制 socket，供 `quell ctl` 使用
+	Safeguards  SafeguardConfig `json:"safeguards"`
+	Audit       AuditConfig     `json:"audit"`
+	Catalogs    []string        `json:"catalogs,omitempty"` // 额外的进程目录文件，优先级最高
+	Batch       BatchConfig     `json:"batch"`
+}
+
+// CatalogPaths 依次合并的进程目录文件 (不存在的会被跳过)：
+// 系统级 /etc/quell/catalog.yaml、个人的 ~/.quell/catalog.yaml、配置文件中列出的文件
+func (c *Config) CatalogPaths() []string {
+	paths := []string{"/etc/quell/catalog.yaml", filepath.Join(Dir(), "catalog.yaml")}
+	return append(paths, c.Catalogs...)
+}
+
+// BatchConfig 批量操作的执行方式，字段与 core.BatchOptions 一一对应
+type BatchConfig struct {
+	Concurrency   int  `json:"concurrency,omitempty"`    // 同时处理的进程数，默认 8
+	ChildrenFirst bool `json:"children_first,omitempty"` // 先处理子孙进程，再处理它们的父进程
+}
+
+// AuditConfig 控制记录每次操作的审计日志 (~/.quell/audit.log)
+type AuditConfig struct {
+	Disabled bool   `json:"disabled,omitempty"`
+	Path     string `json:"path,omitempty"` // 默认 ~/.quell/audit.log
+}
+
+// FilePath 审计日志路径
+func (a AuditConfig) FilePath() string {
+	if a.Path != "" {
+		return a.Path
+	}
+	return filepath.Join(Dir(), "audit.log")
+}
+
+// SafeguardConfig 发送信号前的保护名单，在内置名单 (sshd、显示服务等) 之外追加
+// 字段与 core.SafeguardOptions 一一对应
+type SafeguardConfig struct {
+	Disabled  bool     `json:"disabled,omitempty"`   // 关闭全部保护 (不推荐)
+	Names     []string `json:"names,omitempty"`      // 进程名，不区分大小写
+	Users     []string `json:"users,omitempty"`      // 这些用户的进程
+	Cmdlines  []string `json:"cmdlines,omitempty"`   // 命令行正则
+	Refuse    bool     `json:"refuse,omitempty"`     // 名单内的进程直接拒绝，默认需要输入名字确认
+	AllowRoot bool     `json:"allow_root,omitempty"` // 以 root 运行时不再对 root 的进程要求确认
+}
+
+// MetricsConfig 控制 `quell serve --metrics` 导出的指标
+type MetricsConfig struct {
+	Groups       []GroupRule `json:"groups,omitempty"`
+	MaxProcesses int         `json:"max_processes,omitempty"` // 单进程指标的上限 (按 CPU、内存取前 N 个)，默认 200，-1 关闭
+}
+
+// GroupRule 把进程归入一个分组，按名字或命令行的正则匹配，两者都填时需同时满足
+// 按顺序匹配，第一条命中的规则生效；都不命中的进程归入 "other"
+type GroupRule struct {
+	Name    string `json:"name"`
+	Match   string `json:"match,omitempty"`   // 进程名正则
+	Cmdline string `json:"cmdline,omitempty"` // 命令行正则
+}
+
+// HistoryConfig 控制后台记录的进程历史 (~/.quell/history.ring)
+type HistoryConfig struct {
+	Disabled  bool   `json:"disabled,omitempty"`
+	Path      string `json:"path,omitempty"`        // 默认 ~/.quell/history.ring
+	MaxSizeMB int    `json:"max_size_mb,omitempty"` // 文件大小上限，默认 64
+	Retention string `json:"retention,omitempty"`   // 保留时长，默认 "24h"
+	Interval  string `json:"interval,omitempty"`    // 采样间隔，默认 "15s"
+}
+
+// FilePath 历史文件路径
+func (h HistoryConfig) FilePath() string {
+	if h.Path != "" {
+		return h.Path
+	}
+	return filepath.Join(Dir(), "history.ring")
+}
+
+// MaxBytes 文件大小上限 (字节)
+func (h HistoryConfig) MaxBytes() int64 {
+	if h.MaxSizeMB <= 0 {
+		return 64 << 20
+	}
+	return int64(h.MaxSizeMB) << 20
+}
+
+// RetentionDuration 保留时长，格式错误时使用默认值
+func (h HistoryConfig) RetentionDuration() time.Duration {
+	return parseDuration(h.Retention, 24*time.Hour)
+}
+
+// IntervalDuration 采样间隔，格式错误时使用默认值
+func (h HistoryConfig) IntervalDuration() time.Duration {
+	d := parseDuration(h.Interval, 15*time.Second)
+	if d < time.Second {
+		d = time.Second
+	}
+	return d
+}
+
+func parseDuration(s string, def time.Duration) time.Duration {
+	if s == "" {
+		return def
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return def
+	}
+	return d
+}
+
+// Dir 返回 quell 的数据目录 ~/.quell
+func Dir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".quell")
+}
+
+// RunDir 存放运行中 TUI 会话控制 socket 的目录 ~/.quell/run
+func RunDir() string {
+	return filepath.Join(Dir(), "run")
 }
 
 // Manager 配置管理器
@@ -27,8 +150,7 @@ type Manager struct {
 
 // NewManager 创建管理器，自动定位到 ~/.quell/config.json
 func NewManager() *Manager {
-	home, _ := os.UserHomeDir()
-	configDir := filepath.Join(home, ".quell")
+	configDir := Dir()
 
 	// 确保目录存在
 	_ = os.MkdirAll(configDir, 0755)
@@ -0,0 +1,128 @@
+package core
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// CPUMode 决定 CPU 百分比的归一化方式
+type CPUMode string
+
+const (
+	// CPUModeIrix 以单个核心为 100% (top 默认)，多线程进程可以超过 100%
+	CPUModeIrix CPUMode = "irix"
+	// CPUModeSolaris 以整台机器为 100%，即 Irix 值除以逻辑核心数
+	CPUModeSolaris CPUMode = "solaris"
+)
+
+// minSampleInterval 两次采样间隔过短时 jiffies 精度不足，直接沿用上次的结果
+const minSampleInterval = 500 * time.Millisecond
+
+type procKey struct {
+	pid        int32
+	createTime int64
+}
+
+// sample 是某个进程上一次扫描时的累计计数器以及据此算出的速率
+type sample struct {
+	at      time.Time
+	cpuTime float64 // 累计 CPU 时间 (秒)
+	ioRead  uint64
+	ioWrite uint64
+
+	percent   float64 // 按 Irix 方式计算的结果
+	readRate  float64 // 字节/秒
+	writeRate float64
+}
+
+// accountant 根据两次扫描之间累计计数器的差值和真实经过的时间计算 CPU 占用率与 I/O 速率
+// 以 (PID, CreateTime) 作为身份，PID 被复用时不会串数据
+type accountant struct {
+	mu      sync.Mutex
+	mode    CPUMode
+	numCPU  int
+	samples map[procKey]sample
+}
+
+func newAccountant() *accountant {
+	return &accountant{
+		mode:    CPUModeIrix,
+		numCPU:  runtime.NumCPU(),
+		samples: make(map[procKey]sample),
+	}
+}
+
+func (a *accountant) setMode(mode CPUMode) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if mode != CPUModeSolaris {
+		mode = CPUModeIrix
+	}
+	a.mode = mode
+}
+
+// apply 为 procs 填充 CpuPercent 与 IOReadRate / IOWriteRate
+func (a *accountant) apply(procs []Process, now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	seen := make(map[procKey]bool, len(procs))
+	for i := range procs {
+		p := &procs[i]
+		k := procKey{pid: p.PID, createTime: p.CreateTime}
+		seen[k] = true
+
+		cur := sample{at: now, cpuTime: p.CPUTime, ioRead: p.IOReadBytes, ioWrite: p.IOWriteBytes}
+		prev, ok := a.samples[k]
+		switch {
+		case ok && now.Sub(prev.at) < minSampleInterval:
+			// 间隔太短 (例如多个页面在同一个心跳内刷新)，沿用上次结果
+			cur = prev
+		case ok:
+			elapsed := now.Sub(prev.at).Seconds()
+			cur.percent = (p.CPUTime - prev.cpuTime) / elapsed * 100
+			cur.readRate = counterRate(prev.ioRead, p.IOReadBytes, elapsed)
+			cur.writeRate = counterRate(prev.ioWrite, p.IOWriteBytes, elapsed)
+			a.samples[k] = cur
+		default:
+			// 第一次见到该进程：用生命周期内的平均值 (与 ps 的 %CPU 一致)
+			// 这样新进程在第一次扫描时就有可信的数字，而不是 0
+			if p.CreateTime > 0 {
+				if life := now.Sub(time.UnixMilli(p.CreateTime)).Seconds(); life > 0 {
+					cur.percent = p.CPUTime / life * 100
+					cur.readRate = float64(p.IOReadBytes) / life
+					cur.writeRate = float64(p.IOWriteBytes) / life
+				}
+			}
+			a.samples[k] = cur
+		}
+
+		percent := cur.percent
+		if percent < 0 {
+			percent = 0
+		}
+		if a.mode == CPUModeSolaris && a.numCPU > 0 {
+			percent /= float64(a.numCPU)
+		}
+		p.CpuPercent = percent
+		if p.HasIO {
+			p.IOReadRate = cur.readRate
+			p.IOWriteRate = cur.writeRate
+		}
+	}
+
+	for k := range a.samples {
+		if !seen[k] {
+			delete(a.samples, k)
+		}
+	}
+}
+
+// counterRate 计算单调递增计数器的速率，计数器回绕或重置时返回 0
+func counterRate(prev, cur uint64, elapsed float64) float64 {
+	if cur < prev || elapsed <= 0 {
+		return 0
+	}
+	return float64(cur-prev) / elapsed
+}
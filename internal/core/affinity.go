@@ -0,0 +1,180 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// MaxCPUs 可以设置亲和性的 CPU 编号上限 (不含)，与 glibc 的 CPU_SETSIZE 和 unix.CPUSet 一致
+const MaxCPUs = 1024
+
+// CPUSet 一组 CPU 编号，升序且不重复；nil 表示未知
+type CPUSet []int
+
+// NewCPUSet 排序并去重
+func NewCPUSet(cpus ...int) CPUSet {
+	s := make(CPUSet, 0, len(cpus))
+	seen := make(map[int]bool, len(cpus))
+	for _, c := range cpus {
+		if !seen[c] {
+			seen[c] = true
+			s = append(s, c)
+		}
+	}
+	sort.Ints(s)
+	return s
+}
+
+// ParseCPUSet 解析 "0-3,8" 形式的列表，与 cgroup cpuset 和 taskset -c 的格式一致
+// 编号必须小于 MaxCPUs，在展开区间之前检查，"0-2000000000" 这样的输入不会占用大量内存
+func ParseCPUSet(s string) (CPUSet, error) {
+	var cpus []int
+	for _, part := range strings.Split(strings.TrimSpace(s), ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(part, "-")
+		from, err := strconv.Atoi(lo)
+		if err != nil || from < 0 {
+			return nil, fmt.Errorf("invalid CPU %q", part)
+		}
+		if from >= MaxCPUs {
+			return nil, fmt.Errorf("CPU %d out of range (max %d)", from, MaxCPUs-1)
+		}
+		to := from
+		if isRange {
+			if to, err = strconv.Atoi(hi); err != nil || to < from {
+				return nil, fmt.Errorf("invalid CPU range %q", part)
+			}
+			if to >= MaxCPUs {
+				return nil, fmt.Errorf("CPU %d out of range (max %d)", to, MaxCPUs-1)
+			}
+		}
+		for c := from; c <= to; c++ {
+			cpus = append(cpus, c)
+		}
+	}
+	if len(cpus) == 0 {
+		return nil, fmt.Errorf("empty CPU list %q", s)
+	}
+	return NewCPUSet(cpus...), nil
+}
+
+// String 连续的编号合并为区间，例如 "0-3,8"
+func (s CPUSet) String() string {
+	var parts []string
+	for i := 0; i < len(s); {
+		j := i
+		for j+1 < len(s) && s[j+1] == s[j]+1 {
+			j++
+		}
+		if j > i {
+			parts = append(parts, fmt.Sprintf("%d-%d", s[i], s[j]))
+		} else {
+			parts = append(parts, strconv.Itoa(s[i]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+func (s CPUSet) Contains(cpu int) bool {
+	i := sort.SearchInts(s, cpu)
+	return i < len(s) && s[i] == cpu
+}
+
+// Minus 在 s 中但不在 o 中的 CPU
+func (s CPUSet) Minus(o CPUSet) CPUSet {
+	var out CPUSet
+	for _, c := range s {
+		if !o.Contains(c) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func (s CPUSet) Equal(o CPUSet) bool {
+	if len(s) != len(o) {
+		return false
+	}
+	for i := range s {
+		if s[i] != o[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// AffinityInfo 进程的 CPU 亲和性，以及约束它的 cgroup cpuset
+type AffinityInfo struct {
+	Mask   CPUSet // sched_getaffinity 的结果 (主线程)
+	Cpuset CPUSet // cgroup 的 cpuset.cpus.effective，读不到时为 nil
+	Online CPUSet // 在线的 CPU
+}
+
+// Warnings 把 mask 与 cgroup cpuset 对比：包含 cpuset 之外的 CPU、排除了 cpuset 允许的 CPU，
+// 以及包含离线的 CPU。mask 为 nil 时检查当前的亲和性
+func (a AffinityInfo) Warnings(mask CPUSet) []string {
+	if mask == nil {
+		mask = a.Mask
+	}
+	var warnings []string
+	if a.Cpuset != nil {
+		if outside := mask.Minus(a.Cpuset); len(outside) > 0 {
+			warnings = append(warnings, fmt.Sprintf("CPUs %s are outside the cgroup cpuset (%s) and will not be used", outside, a.Cpuset))
+		}
+		if excluded := a.Cpuset.Minus(mask); len(excluded) > 0 {
+			warnings = append(warnings, fmt.Sprintf("mask excludes CPUs %s that the cgroup cpuset allows", excluded))
+		}
+	}
+	if a.Online != nil {
+		if offline := mask.Minus(a.Online); len(offline) > 0 {
+			warnings = append(warnings, fmt.Sprintf("CPUs %s are offline", offline))
+		}
+	}
+	return warnings
+}
+
+// GetAffinity 读取亲和性、cgroup cpuset 与在线 CPU；后两者读不到时留空，只影响提示
+func (s *Service) GetAffinity(pid int32) (AffinityInfo, error) {
+	mask, err := s.provider.GetAffinity(pid)
+	if err != nil {
+		return AffinityInfo{}, err
+	}
+	info := AffinityInfo{Mask: mask}
+	info.Cpuset, _ = s.provider.GetCpuset(pid)
+	info.Online, _ = s.provider.OnlineCPUs()
+	return info, nil
+}
+
+// SetAffinity 设置进程的 CPU 亲和性；threads 为 true 时对所有线程生效，否则只有主线程
+// (之后新建的线程继承创建者的亲和性)。与 Renice 一样不经过误杀保护
+func (s *Service) SetAffinity(pid int32, cpus CPUSet, threads bool) (err error) {
+	if len(cpus) == 0 {
+		return errors.New("empty CPU list")
+	}
+	detail := "cpus=" + cpus.String()
+	if threads {
+		detail += " threads"
+	}
+	done := s.begin(pid, "affinity", "", detail)
+	defer func() { done(err) }()
+	if err := s.authorize(pid, "pin to CPUs "+cpus.String(), true); err != nil {
+		return err
+	}
+	err = s.provider.SetAffinity(pid, cpus, threads)
+	if errors.Is(err, syscall.EINVAL) {
+		// mask 与 cgroup cpuset (或在线 CPU) 没有交集时内核拒绝设置
+		if cpuset, cerr := s.provider.GetCpuset(pid); cerr == nil {
+			return fmt.Errorf("affinity: none of CPUs %s is usable (cgroup cpuset %s)", cpus, cpuset)
+		}
+		return fmt.Errorf("affinity: none of CPUs %s is usable", cpus)
+	}
+	return priorityError(err, "affinity", "another user's process")
+}
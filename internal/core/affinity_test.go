@@ -0,0 +1,33 @@
+package core
+
+import "testing"
+
+func TestParseCPUSet(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "0-3,8", want: "0-3,8"},
+		{in: " 8, 2,1-2 ,", want: "1-2,8"},
+		{in: "1023", want: "1023"},
+		{in: "1024", wantErr: true},
+		{in: "0-2000000000", wantErr: true},
+		{in: "3-1", wantErr: true},
+		{in: "-1", wantErr: true},
+		{in: "a", wantErr: true},
+		{in: " , ", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCPUSet(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseCPUSet(%q) = %s, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got.String() != tt.want {
+			t.Errorf("ParseCPUSet(%q) = %s, %v, want %s", tt.in, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package core
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Surface 操作的来源
+type Surface string
+
+const (
+	SurfaceKey     Surface = "key"     // TUI 快捷键
+	SurfaceCommand Surface = "command" // TUI 命令模式 (/kill ...)
+	SurfaceControl Surface = "ctl"     // 控制 socket (quell ctl)
+	SurfaceCLI     Surface = "cli"     // 子命令
+	SurfaceAPI     Surface = "api"     // HTTP API
+)
+
+// 审计结果
+const (
+	AuditOK          = "ok"
+	AuditError       = "error"
+	AuditRefused     = "refused"     // 被保护机制拒绝
+	AuditUnconfirmed = "unconfirmed" // 需要确认但未确认
+	AuditDryRun      = "dry-run"
+	AuditReadOnly    = "read-only"
+)
+
+// AuditEntry 审计日志中的一行
+type AuditEntry struct {
+	Time       time.Time `json:"time"`
+	User       string    `json:"user"`
+	SudoUser   string    `json:"sudo_user,omitempty"` // 通过 sudo 运行时的原始用户
+	Surface    Surface   `json:"surface"`
+	Action     string    `json:"action"` // kill、signal、suspend、resume、renice、ionice...
+	PID        int32     `json:"pid"`
+	CreateTime int64     `json:"create_time,omitempty"`
+	Name       string    `json:"name,omitempty"`
+	Cmdline    string    `json:"cmdline,omitempty"`
+	Signal     string    `json:"signal,omitempty"`
+	Detail     string    `json:"detail,omitempty"` // 例如 renice 的目标值
+	Result     string    `json:"result"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Failed 操作是否没有真正生效 (演练除外)
+func (e AuditEntry) Failed() bool {
+	return e.Result != AuditOK && e.Result != AuditDryRun
+}
+
+// AuditLog 只追加的 JSON-lines 审计日志
+// 每条记录一次 write，多个 quell 进程同时追加也不会交错
+type AuditLog struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+}
+
+// OpenAuditLog 打开 (必要时创建) 审计日志，只有当前用户可读写
+func OpenAuditLog(path string) (*AuditLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditLog{path: path, f: f}, nil
+}
+
+// Path 日志文件路径
+func (a *AuditLog) Path() string { return a.path }
+
+// Append 追加一条记录
+func (a *AuditLog) Append(e AuditEntry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, err = a.f.Write(append(line, '\n'))
+	return err
+}
+
+func (a *AuditLog) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.f.Close()
+}
+
+// AuditFilter 浏览日志时的过滤条件，零值表示不过滤
+type AuditFilter struct {
+	Since   time.Time
+	PID     int32
+	Name    string // 进程名或命令行包含 (不区分大小写)
+	Surface Surface
+	Action  string
+	Failed  bool // 只看没有生效的操作
+}
+
+// Match 判断一条记录是否满足条件
+func (f AuditFilter) Match(e AuditEntry) bool {
+	switch {
+	case !f.Since.IsZero() && e.Time.Before(f.Since):
+		return false
+	case f.PID != 0 && e.PID != f.PID:
+		return false
+	case f.Surface != "" && e.Surface != f.Surface:
+		return false
+	case f.Action != "" && !strings.EqualFold(e.Action, f.Action):
+		return false
+	case f.Failed && !e.Failed():
+		return false
+	}
+	if f.Name != "" {
+		needle := strings.ToLower(f.Name)
+		return strings.Contains(strings.ToLower(e.Name), needle) || strings.Contains(strings.ToLower(e.Cmdline), needle)
+	}
+	return true
+}
+
+// ParseAuditFilter 解析 "pid=123 surface=api action=kill since=1h failed nginx" 形式的过滤条件
+// 不带 = 的词按进程名匹配
+func ParseAuditFilter(args []string) (AuditFilter, error) {
+	var f AuditFilter
+	for _, arg := range args {
+		k, v, ok := strings.Cut(arg, "=")
+		if !ok {
+			if arg == "failed" {
+				f.Failed = true
+			} else {
+				f.Name = arg
+			}
+			continue
+		}
+		switch k {
+		case "pid":
+			pid, err := strconv.ParseInt(v, 10, 32)
+			if err != nil {
+				return f, fmt.Errorf("invalid pid %q", v)
+			}
+			f.PID = int32(pid)
+		case "name":
+			f.Name = v
+		case "surface":
+			f.Surface = Surface(v)
+		case "action":
+			f.Action = v
+		case "since":
+			t, err := ParseSince(v, time.Now())
+			if err != nil {
+				return f, err
+			}
+			f.Since = t
+		default:
+			return f, fmt.Errorf("unknown filter %q (pid, name, surface, action, since, failed)", k)
+		}
+	}
+	return f, nil
+}
+
+// ParseSince 接受相对时长 (30m、2h) 或绝对时间 (2006-01-02、2006-01-02T15:04、RFC3339)
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use 30m, 2h or 2006-01-02)", s)
+}
+
+// ReadAuditLog 读取满足条件的记录，按时间从新到旧排列
+// 文件不存在时返回空列表；无法解析的行 (例如写到一半) 会被跳过
+func ReadAuditLog(path string, filter AuditFilter) ([]AuditEntry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e AuditEntry
+		if json.Unmarshal(scanner.Bytes(), &e) != nil {
+			continue
+		}
+		if filter.Match(e) {
+			entries = append(entries, e)
+		}
+	}
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, scanner.Err()
+}
+
+// SetAuditLog 设置审计日志，nil 表示不记录
+func (s *Service) SetAuditLog(a *AuditLog) {
+	s.mu.Lock()
+	s.audit = a
+	s.mu.Unlock()
+}
+
+// AuditLogPath 当前审计日志的路径，未开启时为空
+func (s *Service) AuditLogPath() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.audit == nil {
+		return ""
+	}
+	return s.audit.Path()
+}
+
+var (
+	actorOnce sync.Once
+	actorName string
+	actorSudo string
+)
+
+// actor 运行 quell 的用户，以及 sudo 之前的原始用户
+func actor() (string, string) {
+	actorOnce.Do(func() {
+		if u, err := user.Current(); err == nil {
+			actorName = u.Username
+		}
+		if sudo := os.Getenv("SUDO_USER"); sudo != "" && sudo != actorName {
+			actorSudo = sudo
+		}
+	})
+	return actorName, actorSudo
+}
+
+// begin 在操作之前记下目标进程 (操作之后它可能已经不存在)，返回的函数用结果写入一条记录
+// 未开启审计日志时不做任何查询
+func (s *Service) begin(pid int32, action, sig, detail string) func(error) {
+	s.mu.Lock()
+	a := s.audit
+	s.mu.Unlock()
+	if a == nil {
+		return func(error) {}
+	}
+	p, ok := s.Lookup(pid)
+	if !ok {
+		p = Process{PID: pid}
+	}
+	return func(err error) { s.record(a, p, action, sig, detail, err) }
+}
+
+// record 写入一条记录；写日志失败不影响操作本身
+func (s *Service) record(a *AuditLog, p Process, action, sig, detail string, err error) {
+	e := AuditEntry{
+		Time:       time.Now(),
+		Surface:    s.surface,
+		Action:     action,
+		PID:        p.PID,
+		CreateTime: p.CreateTime,
+		Name:       p.Name,
+		Cmdline:    p.Cmdline,
+		Signal:     sig,
+		Detail:     detail,
+		Result:     auditResult(err),
+	}
+	e.User, e.SudoUser = actor()
+	if e.Surface == "" {
+		e.Surface = SurfaceKey
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	_ = a.Append(e)
+}
+
+// auditResult 按错误类型归类操作结果
+func auditResult(err error) string {
+	switch {
+	case err == nil:
+		return AuditOK
+	case errors.Is(err, ErrDryRun):
+		return AuditDryRun
+	case errors.Is(err, ErrReadOnly):
+		return AuditReadOnly
+	case errors.Is(err, ErrProtected):
+		return AuditRefused
+	case errors.Is(err, ErrNeedsConfirm):
+		return AuditUnconfirmed
+	}
+	return AuditError
+}
@@ -0,0 +1,128 @@
+package core
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// DefaultBatchConcurrency 批量操作默认同时处理的进程数
+const DefaultBatchConcurrency = 8
+
+// BatchOptions 批量操作的执行方式
+type BatchOptions struct {
+	Concurrency   int  // 同时处理的进程数，<= 0 时使用 DefaultBatchConcurrency
+	ChildrenFirst bool // 先处理子孙进程，再处理它们的父进程
+}
+
+// BatchResult 批量操作中一个目标的结果
+type BatchResult struct {
+	Process Process
+	Err     error
+}
+
+// Status 结果分类，与审计日志的 result 一致：ok / dry-run / read-only / refused / unconfirmed / error
+func (r BatchResult) Status() string {
+	return auditResult(r.Err)
+}
+
+// Failed 操作没有生效 (演练模式除外)，可以重试；目标已退出的不算，重试也没有意义
+func (r BatchResult) Failed() bool {
+	return r.Err != nil && !errors.Is(r.Err, ErrDryRun) && !errors.Is(r.Err, ErrProcessExited)
+}
+
+// RunBatch 对 procs 逐个执行 op，最多同时执行 opts.Concurrency 个
+// 结果与 procs 一一对应；ChildrenFirst 时按进程树分层，
+// 最深的一层全部完成后才开始上一层，保证子进程总是先于父进程处理
+// 执行前按 CreateTime 核对身份：目标已退出或 PID 被复用时不执行 op，结果为 ErrProcessExited
+func (s *Service) RunBatch(procs []Process, opts BatchOptions, op func(pid int32) error) []BatchResult {
+	results := make([]BatchResult, len(procs))
+	for i, p := range procs {
+		results[i].Process = p
+	}
+
+	limit := opts.Concurrency
+	if limit <= 0 {
+		limit = DefaultBatchConcurrency
+	}
+	waves := [][]int{make([]int, len(procs))}
+	for i := range procs {
+		waves[0][i] = i
+	}
+	if opts.ChildrenFirst {
+		waves = s.batchWaves(procs)
+	}
+
+	sem := make(chan struct{}, limit)
+	for _, wave := range waves {
+		var wg sync.WaitGroup
+		for _, i := range wave {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(i int) {
+				defer func() { <-sem; wg.Done() }()
+				if !s.sameProcess(procs[i]) {
+					results[i].Err = ErrProcessExited
+					return
+				}
+				results[i].Err = op(procs[i].PID)
+			}(i)
+		}
+		wg.Wait()
+	}
+	return results
+}
+
+// sameProcess PID 对应的仍是 p 这个进程；没有记录 CreateTime 时无法核对，视为相同
+func (s *Service) sameProcess(p Process) bool {
+	if p.CreateTime == 0 {
+		return true
+	}
+	ct, err := s.provider.GetCreateTime(p.PID)
+	return err == nil && ct == p.CreateTime
+}
+
+// batchWaves 按 "在目标中有几个祖先" 分层，返回从深到浅的下标分组
+// 祖先关系取自最近一次扫描，中间隔着非目标进程也算
+func (s *Service) batchWaves(procs []Process) [][]int {
+	s.mu.Lock()
+	parents := make(map[int32]int32, len(s.known))
+	for pid, p := range s.known {
+		parents[pid] = p.PPID
+	}
+	s.mu.Unlock()
+	for _, p := range procs {
+		if _, ok := parents[p.PID]; !ok {
+			parents[p.PID] = p.PPID
+		}
+	}
+
+	targets := make(map[int32]bool, len(procs))
+	for _, p := range procs {
+		targets[p.PID] = true
+	}
+
+	byDepth := make(map[int][]int)
+	for i, p := range procs {
+		depth := 0
+		seen := map[int32]bool{p.PID: true}
+		for pid := parents[p.PID]; pid > 0 && !seen[pid]; pid = parents[pid] {
+			seen[pid] = true
+			if targets[pid] {
+				depth++
+			}
+		}
+		byDepth[depth] = append(byDepth[depth], i)
+	}
+
+	depths := make([]int, 0, len(byDepth))
+	for d := range byDepth {
+		depths = append(depths, d)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(depths)))
+	waves := make([][]int, len(depths))
+	for i, d := range depths {
+		waves[i] = byDepth[d]
+	}
+	return waves
+}
@@ -0,0 +1,277 @@
+package core
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// CatalogPolicy 目录中为一类进程约定的处理方式
+type CatalogPolicy int
+
+const (
+	PolicyNone  CatalogPolicy = iota // 只提供说明
+	PolicySafe                       // 可以放心杀掉
+	PolicyWarn                       // 需要输入确认
+	PolicyNever                      // 禁止发送信号
+)
+
+func (p CatalogPolicy) String() string {
+	switch p {
+	case PolicySafe:
+		return "safe to kill"
+	case PolicyWarn:
+		return "warn"
+	case PolicyNever:
+		return "never kill"
+	default:
+		return ""
+	}
+}
+
+// ParseCatalogPolicy 解析 policy 字段：never、warn、safe
+func ParseCatalogPolicy(s string) (CatalogPolicy, error) {
+	switch strings.ToLower(strings.NewReplacer("-", "", "_", "", " ", "").Replace(s)) {
+	case "":
+		return PolicyNone, nil
+	case "safe", "safetokill":
+		return PolicySafe, nil
+	case "warn":
+		return PolicyWarn, nil
+	case "never", "neverkill":
+		return PolicyNever, nil
+	}
+	return PolicyNone, fmt.Errorf("unknown policy %q (never, warn or safe)", s)
+}
+
+// CatalogEntry 目录中的一条：匹配条件 + 说明 + 处理方式
+// 匹配条件之间是 "且" 的关系，至少需要一个
+type CatalogEntry struct {
+	ID          string // 合并多个目录文件时用于识别同一条，默认取 name / match / cmdline
+	Name        string // 进程名，精确匹配，不区分大小写
+	Match       string // 进程名正则
+	Cmdline     string // 命令行正则
+	User        string
+	Description string
+	Owner       string // 负责的团队
+	Runbook     string // 处理步骤，可多行
+	Policy      CatalogPolicy
+	Source      string // 定义 (或最后覆盖) 这一条的文件与行号
+
+	match   *regexp.Regexp
+	cmdline *regexp.Regexp
+}
+
+// Badge 列表中显示的简短标记，例如 "[platform · never kill]"
+func (e *CatalogEntry) Badge() string {
+	var parts []string
+	if e.Owner != "" {
+		parts = append(parts, e.Owner)
+	}
+	if e.Policy != PolicyNone {
+		parts = append(parts, e.Policy.String())
+	}
+	if len(parts) == 0 {
+		return "[catalog]"
+	}
+	return "[" + strings.Join(parts, " · ") + "]"
+}
+
+// Matches 判断进程是否属于这一条
+func (e *CatalogEntry) Matches(p Process) bool {
+	if e.Name != "" && !strings.EqualFold(e.Name, p.Name) {
+		return false
+	}
+	if e.match != nil && !e.match.MatchString(p.Name) {
+		return false
+	}
+	if e.cmdline != nil && !e.cmdline.MatchString(p.Cmdline) {
+		return false
+	}
+	if e.User != "" && e.User != p.User {
+		return false
+	}
+	return true
+}
+
+// compile 校验并编译正则，补全 ID
+func (e *CatalogEntry) compile() error {
+	if e.Name == "" && e.Match == "" && e.Cmdline == "" {
+		return errors.New("entry needs at least one of name, match or cmdline")
+	}
+	var err error
+	e.match, e.cmdline = nil, nil
+	if e.Match != "" {
+		if e.match, err = regexp.Compile(e.Match); err != nil {
+			return fmt.Errorf("match: %w", err)
+		}
+	}
+	if e.Cmdline != "" {
+		if e.cmdline, err = regexp.Compile(e.Cmdline); err != nil {
+			return fmt.Errorf("cmdline: %w", err)
+		}
+	}
+	if e.ID == "" {
+		switch {
+		case e.Name != "":
+			e.ID = strings.ToLower(e.Name)
+		case e.Match != "":
+			e.ID = "match:" + e.Match
+		default:
+			e.ID = "cmdline:" + e.Cmdline
+		}
+	}
+	return nil
+}
+
+// mergeFrom 用后加载文件中的非空字段覆盖当前条目
+func (e *CatalogEntry) mergeFrom(o *CatalogEntry) {
+	set := func(dst *string, src string) {
+		if src != "" {
+			*dst = src
+		}
+	}
+	set(&e.Name, o.Name)
+	set(&e.Match, o.Match)
+	set(&e.Cmdline, o.Cmdline)
+	set(&e.User, o.User)
+	set(&e.Description, o.Description)
+	set(&e.Owner, o.Owner)
+	set(&e.Runbook, o.Runbook)
+	set(&e.Source, o.Source)
+	if o.Policy != PolicyNone {
+		e.Policy = o.Policy
+	}
+}
+
+// Catalog 合并后的进程目录
+// 后加载的文件优先：同一 ID 的条目逐字段覆盖，其余条目排在前面的文件之前；同一文件内按书写顺序匹配
+type Catalog struct {
+	entries []*CatalogEntry
+	layers  [][]*CatalogEntry
+	byID    map[string]*CatalogEntry
+}
+
+//go:embed catalog.yaml
+var defaultCatalog string
+
+// DefaultCatalog 随 quell 一起发布的目录，收录常见的系统守护进程
+func DefaultCatalog() *Catalog {
+	c := &Catalog{byID: make(map[string]*CatalogEntry)}
+	if entries, err := ParseCatalog(defaultCatalog, "built-in"); err == nil {
+		_ = c.Add(entries)
+	}
+	return c
+}
+
+// LoadCatalog 在内置目录之上依次合并 paths 中的文件
+// 不存在的文件直接跳过；有错误的文件整个跳过并返回错误，不影响其他文件
+func LoadCatalog(paths []string) (*Catalog, []error) {
+	c := DefaultCatalog()
+	var errs []error
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err == nil {
+			var entries []*CatalogEntry
+			if entries, err = ParseCatalog(string(data), path); err == nil {
+				err = c.Add(entries)
+			}
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return c, errs
+}
+
+// Add 合并一个文件的条目，作为优先级最高的一层；有任何一条无效时不做修改
+func (c *Catalog) Add(entries []*CatalogEntry) error {
+	for _, e := range entries {
+		if err := e.compile(); err != nil {
+			return fmt.Errorf("%s: %w", e.Source, err)
+		}
+	}
+
+	var layer []*CatalogEntry
+	for _, e := range entries {
+		old, ok := c.byID[e.ID]
+		if !ok {
+			c.byID[e.ID] = e
+			layer = append(layer, e)
+			continue
+		}
+		// 被覆盖的条目合并字段后提到新的一层；同一文件内重复的 ID 只合并字段
+		old.mergeFrom(e)
+		_ = old.compile()
+		if containsEntry(layer, old) {
+			continue
+		}
+		for i, l := range c.layers {
+			for j, x := range l {
+				if x == old {
+					c.layers[i] = append(l[:j:j], l[j+1:]...)
+					break
+				}
+			}
+		}
+		layer = append(layer, old)
+	}
+	c.layers = append(c.layers, layer)
+
+	c.entries = c.entries[:0]
+	for i := len(c.layers) - 1; i >= 0; i-- {
+		c.entries = append(c.entries, c.layers[i]...)
+	}
+	return nil
+}
+
+func containsEntry(l []*CatalogEntry, e *CatalogEntry) bool {
+	for _, x := range l {
+		if x == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup 返回第一条匹配的条目，没有时返回 nil
+func (c *Catalog) Lookup(p Process) *CatalogEntry {
+	if c == nil {
+		return nil
+	}
+	for _, e := range c.entries {
+		if e.Matches(p) {
+			return e
+		}
+	}
+	return nil
+}
+
+// Len 条目数量
+func (c *Catalog) Len() int {
+	if c == nil {
+		return 0
+	}
+	return len(c.entries)
+}
+
+// SetCatalog 设置进程目录，nil 表示不使用
+func (s *Service) SetCatalog(c *Catalog) {
+	s.mu.Lock()
+	s.catalog = c
+	s.mu.Unlock()
+}
+
+// CatalogFor 查询进程在目录中的条目
+func (s *Service) CatalogFor(p Process) *CatalogEntry {
+	s.mu.Lock()
+	c := s.catalog
+	s.mu.Unlock()
+	return c.Lookup(p)
+}
@@ -0,0 +1,230 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseCatalog 解析目录文件。只支持 YAML 的一个子集，足够书写目录：
+//
+//	# 注释
+//	processes:              # 可省略，直接写列表也可以
+//	  - name: vmtoolsd
+//	    description: "VMware Tools 守护进程"
+//	    owner: platform
+//	    policy: warn        # never / warn / safe
+//	    runbook: |
+//	      多行文本，保留换行
+//
+// 标量可以不加引号、用单引号或双引号；多行文本用 | (保留换行) 或 > (折叠为一行)
+func ParseCatalog(text, source string) ([]*CatalogEntry, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	var entries []*CatalogEntry
+	var cur *CatalogEntry
+	itemIndent := -1
+
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		raw := lines[i]
+		trimmed := strings.TrimSpace(raw)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if strings.HasPrefix(strings.TrimLeft(raw, " "), "\t") {
+			return nil, fmt.Errorf("%s:%d: tabs are not allowed for indentation", source, lineNo)
+		}
+		indent := len(raw) - len(strings.TrimLeft(raw, " "))
+		keyIndent := indent
+		body := trimmed
+
+		// 顶层的 processes: 只是容器
+		if indent == 0 && !strings.HasPrefix(body, "-") {
+			key, value, _ := strings.Cut(body, ":")
+			if strings.TrimSpace(key) == "processes" && stripComment(strings.TrimSpace(value)) == "" {
+				continue
+			}
+			return nil, fmt.Errorf("%s:%d: expected a list item (\"- name: ...\") or \"processes:\"", source, lineNo)
+		}
+
+		if body == "-" || strings.HasPrefix(body, "- ") {
+			cur = &CatalogEntry{Source: fmt.Sprintf("%s:%d", source, lineNo)}
+			entries = append(entries, cur)
+			itemIndent = indent + 2
+			keyIndent = itemIndent
+			body = strings.TrimSpace(strings.TrimPrefix(body, "-"))
+			if body == "" {
+				continue
+			}
+		} else if cur == nil || indent < itemIndent {
+			return nil, fmt.Errorf("%s:%d: unexpected indentation", source, lineNo)
+		}
+
+		key, value, ok := strings.Cut(body, ":")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected \"key: value\"", source, lineNo)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		if style := stripComment(value); style == "|" || style == ">" || style == "|-" || style == ">-" {
+			var block []string
+			block, i = readBlock(lines, i+1, keyIndent)
+			if style[0] == '>' {
+				value = foldBlock(block)
+			} else {
+				value = strings.Join(block, "\n")
+			}
+		} else {
+			v, err := parseScalar(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %s: %w", source, lineNo, key, err)
+			}
+			value = v
+		}
+
+		if err := setCatalogField(cur, key, value); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", source, lineNo, err)
+		}
+	}
+	return entries, nil
+}
+
+func setCatalogField(e *CatalogEntry, key, value string) error {
+	switch key {
+	case "id":
+		e.ID = value
+	case "name":
+		e.Name = value
+	case "match":
+		e.Match = value
+	case "cmdline":
+		e.Cmdline = value
+	case "user":
+		e.User = value
+	case "description":
+		e.Description = value
+	case "owner":
+		e.Owner = value
+	case "runbook":
+		e.Runbook = value
+	case "policy":
+		p, err := ParseCatalogPolicy(value)
+		if err != nil {
+			return err
+		}
+		e.Policy = p
+	default:
+		return fmt.Errorf("unknown key %q", key)
+	}
+	return nil
+}
+
+// readBlock 读取 | 或 > 之后缩进比 parent 更深的行，去掉公共缩进；返回最后一行的下标
+func readBlock(lines []string, start, parent int) ([]string, int) {
+	var block []string
+	indent := -1
+	end := start - 1
+	for i := start; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			block = append(block, "")
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " "))
+		if n <= parent {
+			break
+		}
+		if indent < 0 {
+			indent = n
+		}
+		if n < indent {
+			line = strings.Repeat(" ", indent) + strings.TrimLeft(line, " ")
+		}
+		block = append(block, line[indent:])
+		end = i
+	}
+	// 末尾的空行不属于文本
+	for len(block) > 0 && block[len(block)-1] == "" {
+		block = block[:len(block)-1]
+	}
+	return block, end
+}
+
+// foldBlock > 风格：相邻的行以空格连接，空行变成换行
+func foldBlock(block []string) string {
+	var b strings.Builder
+	for i, line := range block {
+		switch {
+		case line == "":
+			b.WriteString("\n")
+		case i > 0 && block[i-1] != "":
+			b.WriteString(" " + line)
+		default:
+			b.WriteString(line)
+		}
+	}
+	return b.String()
+}
+
+// parseScalar 去掉引号或行尾注释
+func parseScalar(v string) (string, error) {
+	switch {
+	case strings.HasPrefix(v, `"`):
+		end := closingQuote(v)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string")
+		}
+		s, err := strconv.Unquote(v[:end+1])
+		if err != nil {
+			return "", fmt.Errorf("invalid string %s", v[:end+1])
+		}
+		if rest := stripComment(strings.TrimSpace(v[end+1:])); rest != "" {
+			return "", fmt.Errorf("unexpected text after string: %s", rest)
+		}
+		return s, nil
+	case strings.HasPrefix(v, "'"):
+		var b strings.Builder
+		for i := 1; i < len(v); i++ {
+			if v[i] != '\'' {
+				b.WriteByte(v[i])
+				continue
+			}
+			if i+1 < len(v) && v[i+1] == '\'' {
+				b.WriteByte('\'')
+				i++
+				continue
+			}
+			if rest := stripComment(strings.TrimSpace(v[i+1:])); rest != "" {
+				return "", fmt.Errorf("unexpected text after string: %s", rest)
+			}
+			return b.String(), nil
+		}
+		return "", fmt.Errorf("unterminated string")
+	}
+	return stripComment(v), nil
+}
+
+// closingQuote 双引号字符串结束的位置，跳过转义
+func closingQuote(v string) int {
+	for i := 1; i < len(v); i++ {
+		switch v[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// stripComment 去掉未加引号的值后面的 " # 注释"
+func stripComment(v string) string {
+	if strings.HasPrefix(v, "#") {
+		return ""
+	}
+	if i := strings.Index(v, " #"); i >= 0 {
+		v = v[:i]
+	}
+	return strings.TrimSpace(v)
+}
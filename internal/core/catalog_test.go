@@ -0,0 +1,211 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCatalog(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []CatalogEntry // 只比较解析出的字段
+	}{
+		{
+			name: "container and comments",
+			text: "# 目录\nprocesses:   # 容器\n  - name: vmtoolsd   # 行尾注释\n    owner: platform\n    policy: warn\n",
+			want: []CatalogEntry{{Name: "vmtoolsd", Owner: "platform", Policy: PolicyWarn}},
+		},
+		{
+			name: "bare list",
+			text: "- name: a\n-\n  name: b\n  policy: never kill\n",
+			want: []CatalogEntry{{Name: "a"}, {Name: "b", Policy: PolicyNever}},
+		},
+		{
+			name: "quoted scalars",
+			text: "- name: \"corp # agent\"\n  match: '^java$'\n  cmdline: 'it''s'\n  description: \"tab\\there\" # 注释\n",
+			want: []CatalogEntry{{Name: "corp # agent", Match: "^java$", Cmdline: "it's", Description: "tab\there"}},
+		},
+		{
+			name: "literal block",
+			text: "- name: a\n  runbook: |\n    line one\n      indented\n\n    line three\n\n  owner: ops\n",
+			want: []CatalogEntry{{Name: "a", Runbook: "line one\n  indented\n\nline three", Owner: "ops"}},
+		},
+		{
+			name: "folded block",
+			text: "- name: a\n  description: >\n    first\n    second\n\n    third\n",
+			want: []CatalogEntry{{Name: "a", Description: "first second\nthird"}},
+		},
+		{
+			name: "crlf",
+			text: "- name: a\r\n  policy: safe\r\n",
+			want: []CatalogEntry{{Name: "a", Policy: PolicySafe}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCatalog(tt.text, "test.yaml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d entries, want %d", len(got), len(tt.want))
+			}
+			for i, w := range tt.want {
+				g := *got[i]
+				g.Source = ""
+				if g != w {
+					t.Errorf("entry %d = %+v, want %+v", i, g, w)
+				}
+			}
+		})
+	}
+}
+
+func TestParseCatalogErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"tab indentation", "- name: a\n\towner: ops\n", "test.yaml:2: tabs"},
+		{"unknown key", "- name: a\n  colour: red\n", `test.yaml:2: unknown key "colour"`},
+		{"bad policy", "- name: a\n\n  policy: maybe\n", `test.yaml:3: unknown policy "maybe"`},
+		{"unterminated string", "- name: \"a\n", "test.yaml:1: name: unterminated string"},
+		{"text after string", "- name: 'a' b\n", "test.yaml:1: name: unexpected text after string: b"},
+		{"top-level key", "owner: ops\n", "test.yaml:1: expected a list item"},
+		{"field before item", "  name: a\n", "test.yaml:1: unexpected indentation"},
+		{"missing colon", "- name: a\n  owner\n", `test.yaml:2: expected "key: value"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCatalog(tt.text, "test.yaml")
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want prefix %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalogAdd(t *testing.T) {
+	layer := func(text string) []*CatalogEntry {
+		t.Helper()
+		entries, err := ParseCatalog(text, "test.yaml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return entries
+	}
+	ids := func(c *Catalog) string {
+		var l []string
+		for _, e := range c.entries {
+			l = append(l, e.ID)
+		}
+		return strings.Join(l, ",")
+	}
+
+	tests := []struct {
+		name    string
+		layers  []string
+		wantIDs string
+		check   func(t *testing.T, c *Catalog)
+	}{
+		{
+			name:    "file order kept",
+			layers:  []string{"- name: a\n- name: b\n- match: '^c'\n"},
+			wantIDs: "a,b,match:^c",
+		},
+		{
+			name:    "later file first",
+			layers:  []string{"- name: a\n- name: b\n", "- name: c\n- name: d\n"},
+			wantIDs: "c,d,a,b",
+		},
+		{
+			name:    "override moves entry to its new layer",
+			layers:  []string{"- name: a\n- name: b\n", "- name: c\n- name: B\n  owner: ops\n"},
+			wantIDs: "c,b,a",
+		},
+		{
+			name:    "fields merged",
+			layers:  []string{"- name: a\n  description: first\n  policy: never\n", "- name: a\n  owner: ops\n"},
+			wantIDs: "a",
+			check: func(t *testing.T, c *Catalog) {
+				e := c.Lookup(Process{Name: "a"})
+				if e.Description != "first" || e.Owner != "ops" || e.Policy != PolicyNever {
+					t.Errorf("merged entry = %+v", *e)
+				}
+				if e.Source != "test.yaml:1" {
+					t.Errorf("Source = %q, want the overriding line", e.Source)
+				}
+			},
+		},
+		{
+			name:    "duplicate id in one file",
+			layers:  []string{"- name: a\n- name: b\n- name: a\n  owner: ops\n"},
+			wantIDs: "a,b",
+			check: func(t *testing.T, c *Catalog) {
+				if e := c.Lookup(Process{Name: "a"}); e.Owner != "ops" {
+					t.Errorf("Owner = %q", e.Owner)
+				}
+			},
+		},
+		{
+			name:    "explicit id overrides by id",
+			layers:  []string{"- id: kafka\n  match: '^java$'\n  cmdline: kafka\n", "- id: kafka\n  match: '^java$'\n  owner: data\n"},
+			wantIDs: "kafka",
+			check: func(t *testing.T, c *Catalog) {
+				if e := c.Lookup(Process{Name: "java", Cmdline: "java kafka.Kafka"}); e == nil || e.Owner != "data" {
+					t.Errorf("Lookup = %+v", e)
+				}
+				if e := c.Lookup(Process{Name: "java"}); e != nil {
+					t.Errorf("cmdline condition lost: %+v", *e)
+				}
+			},
+		},
+		{
+			name:    "later layer matches first",
+			layers:  []string{"- match: '^post'\n  owner: dba\n", "- name: postgres\n  owner: platform\n"},
+			wantIDs: "postgres,match:^post",
+			check: func(t *testing.T, c *Catalog) {
+				if e := c.Lookup(Process{Name: "postgres"}); e.Owner != "platform" {
+					t.Errorf("Owner = %q", e.Owner)
+				}
+				if e := c.Lookup(Process{Name: "postmaster"}); e.Owner != "dba" {
+					t.Errorf("Owner = %q", e.Owner)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Catalog{byID: make(map[string]*CatalogEntry)}
+			for _, l := range tt.layers {
+				if err := c.Add(layer(l)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := ids(c); got != tt.wantIDs {
+				t.Errorf("order = %s, want %s", got, tt.wantIDs)
+			}
+			if tt.check != nil {
+				tt.check(t, c)
+			}
+		})
+	}
+}
+
+func TestCatalogAddInvalidLeavesCatalogUnchanged(t *testing.T) {
+	c := &Catalog{byID: make(map[string]*CatalogEntry)}
+	first, _ := ParseCatalog("- name: a\n", "one.yaml")
+	if err := c.Add(first); err != nil {
+		t.Fatal(err)
+	}
+	bad, _ := ParseCatalog("- name: b\n- match: '('\n", "two.yaml")
+	err := c.Add(bad)
+	if err == nil || !strings.HasPrefix(err.Error(), "two.yaml:2: match:") {
+		t.Fatalf("err = %v", err)
+	}
+	if c.Len() != 1 || c.Lookup(Process{Name: "b"}) != nil {
+		t.Errorf("invalid file was partly merged")
+	}
+}
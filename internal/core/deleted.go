@@ -0,0 +1,88 @@
+package core
+
+import "sort"
+
+type fileID struct{ dev, ino uint64 }
+
+func (f OpenFile) id() (fileID, bool) {
+	return fileID{f.Device, f.Inode}, f.Inode != 0
+}
+
+// FindDeletedFiles 扫描所有进程，找出“已删除但仍被打开”的普通文件
+// 同一进程的多个 fd 按 inode 合并为一条；结果按持有者可释放的总量降序排列，
+// 同一持有者内部按文件大小降序。无权限读取的进程会被静默跳过
+func (s *Service) FindDeletedFiles() ([]DeletedFile, error) {
+	procs, err := s.GetProcesses()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []DeletedFile
+	holders := make(map[fileID]int)
+	for _, p := range procs {
+		files, err := s.provider.GetOpenFiles(p.PID)
+		if err != nil {
+			continue
+		}
+		first := len(results)
+		index := make(map[fileID]int)
+		for _, f := range files {
+			if !f.Deleted || f.Type != "file" {
+				continue
+			}
+			id, ok := f.id()
+			if i, seen := index[id]; ok && seen {
+				results[i].Fds = append(results[i].Fds, f.Fd)
+				continue
+			}
+			if ok {
+				index[id] = len(results)
+				holders[id]++
+			}
+			results = append(results, DeletedFile{Holder: p, File: f, Fds: []int{f.Fd}, Holders: 1})
+		}
+		var total uint64
+		for _, r := range results[first:] {
+			total += uint64(max(0, r.File.Size))
+		}
+		for i := first; i < len(results); i++ {
+			results[i].HolderTotal = total
+		}
+	}
+	for i := range results {
+		if id, ok := results[i].File.id(); ok {
+			results[i].Holders = holders[id]
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.HolderTotal != b.HolderTotal {
+			return a.HolderTotal > b.HolderTotal
+		}
+		if a.Holder.PID != b.Holder.PID {
+			return a.Holder.PID < b.Holder.PID
+		}
+		return a.File.Size > b.File.Size
+	})
+	return results, nil
+}
+
+// ReclaimableBytes 计算关闭这些文件后可以释放的空间
+// 同一个 inode 可能被多个进程持有，只计算一次
+func ReclaimableBytes(files []DeletedFile) uint64 {
+	seen := make(map[fileID]bool)
+	var total uint64
+	for _, f := range files {
+		if id, ok := f.File.id(); ok {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+		}
+		if f.File.Size > 0 {
+			total += uint64(f.File.Size)
+		}
+	}
+	return total
+}
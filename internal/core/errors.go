@@ -1 +1,12 @@
 package core
+
+import "errors"
+
+var (
+	// ErrPermissionDenied 读取 /proc 等信息时权限不足
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrNotSupported 当前平台不支持该操作
+	ErrNotSupported = errors.New("not supported on this platform")
+	// ErrProcessExited 目标进程已经退出，或者 PID 已被新进程复用
+	ErrProcessExited = errors.New("process exited")
+)
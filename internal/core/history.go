@@ -0,0 +1,318 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 历史文件格式：64 字节文件头 + 固定 64 字节的记录组成的环形缓冲区
+// 写满后从头覆盖最旧的记录，文件大小因此有上限
+const (
+	historyMagic      = "QUELLHST"
+	historyVersion    = 1
+	historyHeaderSize = 64
+	historyRecordSize = 64
+	historyNameLen    = 16
+	historyMaxPorts   = 3
+)
+
+// ErrHistoryCorrupt 历史文件头无法识别
+var ErrHistoryCorrupt = errors.New("history file is corrupt or has an unknown format")
+
+// HistorySample 某个进程在某一时刻的采样
+type HistorySample struct {
+	Time       time.Time `json:"time"`
+	PID        int32     `json:"pid"`
+	CreateTime int64     `json:"create_time"`
+	Name       string    `json:"name"` // 最多保存 16 字节
+	CPU        float64   `json:"cpu_percent"`
+	RSS        uint64    `json:"rss_bytes"`
+	HasIO      bool      `json:"has_io"`
+	IORead     float64   `json:"io_read_bps"`     // 字节/秒
+	IOWrite    float64   `json:"io_write_bps"`    // 字节/秒
+	Ports      []int     `json:"ports,omitempty"` // 最多保存 3 个
+	PortCount  int       `json:"port_count"`      // 实际监听的端口数
+}
+
+// SampleFromProcess 从一次扫描结果构造采样
+func SampleFromProcess(p Process, t time.Time) HistorySample {
+	return HistorySample{
+		Time:       t,
+		PID:        p.PID,
+		CreateTime: p.CreateTime,
+		Name:       p.Name,
+		CPU:        p.CpuPercent,
+		RSS:        p.MemoryUsage,
+		HasIO:      p.HasIO,
+		IORead:     p.IOReadRate,
+		IOWrite:    p.IOWriteRate,
+		Ports:      p.Ports,
+		PortCount:  len(p.Ports),
+	}
+}
+
+// 记录布局 (小端)：
+//
+//	0  ts (unix 毫秒)   int64
+//	8  create time      int64
+//	16 pid              int32
+//	20 cpu %            float32
+//	24 rss              uint64
+//	32 read 字节/秒     float32
+//	36 write 字节/秒    float32
+//	40 ports            [3]uint16
+//	46 port count       uint8
+//	47 flags            uint8 (bit0: HasIO)
+//	48 name             [16]byte
+func (s HistorySample) encode(buf []byte) {
+	le := binary.LittleEndian
+	le.PutUint64(buf[0:], uint64(s.Time.UnixMilli()))
+	le.PutUint64(buf[8:], uint64(s.CreateTime))
+	le.PutUint32(buf[16:], uint32(s.PID))
+	le.PutUint32(buf[20:], math.Float32bits(float32(s.CPU)))
+	le.PutUint64(buf[24:], s.RSS)
+	le.PutUint32(buf[32:], math.Float32bits(float32(s.IORead)))
+	le.PutUint32(buf[36:], math.Float32bits(float32(s.IOWrite)))
+	for i := 0; i < historyMaxPorts; i++ {
+		var port uint16
+		if i < len(s.Ports) {
+			port = uint16(s.Ports[i])
+		}
+		le.PutUint16(buf[40+i*2:], port)
+	}
+	buf[46] = uint8(min(s.PortCount, math.MaxUint8))
+	buf[47] = 0
+	if s.HasIO {
+		buf[47] = 1
+	}
+	name := make([]byte, historyNameLen)
+	copy(name, s.Name)
+	copy(buf[48:], name)
+}
+
+func decodeSample(buf []byte) HistorySample {
+	le := binary.LittleEndian
+	s := HistorySample{
+		Time:       time.UnixMilli(int64(le.Uint64(buf[0:]))),
+		CreateTime: int64(le.Uint64(buf[8:])),
+		PID:        int32(le.Uint32(buf[16:])),
+		CPU:        float64(math.Float32frombits(le.Uint32(buf[20:]))),
+		RSS:        le.Uint64(buf[24:]),
+		IORead:     float64(math.Float32frombits(le.Uint32(buf[32:]))),
+		IOWrite:    float64(math.Float32frombits(le.Uint32(buf[36:]))),
+		PortCount:  int(buf[46]),
+		HasIO:      buf[47]&1 != 0,
+		Name:       string(bytes.TrimRight(buf[48:48+historyNameLen], "\x00")),
+	}
+	for i := 0; i < historyMaxPorts && i < s.PortCount; i++ {
+		s.Ports = append(s.Ports, int(le.Uint16(buf[40+i*2:])))
+	}
+	return s
+}
+
+// HistoryStore 磁盘上的环形历史文件
+type HistoryStore struct {
+	mu        sync.Mutex
+	path      string
+	capacity  uint64 // 可容纳的记录数
+	retention time.Duration
+}
+
+// OpenHistoryStore 打开 (必要时创建) 历史文件
+// maxBytes 限制文件大小，retention 之前的记录在查询时被忽略，并最终被新记录覆盖
+// 若已有文件的容量与 maxBytes 不一致，会丢弃旧数据重建
+func OpenHistoryStore(path string, maxBytes int64, retention time.Duration) (*HistoryStore, error) {
+	capacity := uint64((maxBytes - historyHeaderSize) / historyRecordSize)
+	if capacity < 1 {
+		return nil, fmt.Errorf("history size limit %d is too small", maxBytes)
+	}
+	h := &HistoryStore{path: path, capacity: capacity, retention: retention}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	hdr, err := readHistoryHeader(f)
+	if err == nil && hdr.capacity == capacity {
+		return h, nil
+	}
+	// 新文件、格式不对或容量改变：重建
+	if err := f.Truncate(0); err != nil {
+		return nil, err
+	}
+	if err := writeHistoryHeader(f, historyHeader{capacity: capacity}); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Path 历史文件路径
+func (h *HistoryStore) Path() string { return h.path }
+
+type historyHeader struct {
+	capacity uint64
+	head     uint64 // 下一条记录写入的位置
+	count    uint64 // 已写入的记录数 (不超过 capacity)
+}
+
+func readHistoryHeader(f *os.File) (historyHeader, error) {
+	buf := make([]byte, historyHeaderSize)
+	if _, err := f.ReadAt(buf, 0); err != nil {
+		return historyHeader{}, err
+	}
+	le := binary.LittleEndian
+	if string(buf[:8]) != historyMagic || le.Uint32(buf[8:]) != historyVersion || le.Uint32(buf[12:]) != historyRecordSize {
+		return historyHeader{}, ErrHistoryCorrupt
+	}
+	return historyHeader{
+		capacity: le.Uint64(buf[16:]),
+		head:     le.Uint64(buf[24:]),
+		count:    le.Uint64(buf[32:]),
+	}, nil
+}
+
+func writeHistoryHeader(f *os.File, hdr historyHeader) error {
+	buf := make([]byte, historyHeaderSize)
+	le := binary.LittleEndian
+	copy(buf, historyMagic)
+	le.PutUint32(buf[8:], historyVersion)
+	le.PutUint32(buf[12:], historyRecordSize)
+	le.PutUint64(buf[16:], hdr.capacity)
+	le.PutUint64(buf[24:], hdr.head)
+	le.PutUint64(buf[32:], hdr.count)
+	_, err := f.WriteAt(buf, 0)
+	return err
+}
+
+// Append 追加一批采样
+// 每次都在文件锁内重新读取文件头，同时运行的多个 quell 实例 (例如 tmux 的多个窗格)
+// 不会互相覆盖写入位置
+func (h *HistoryStore) Append(samples []HistorySample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	f, err := os.OpenFile(h.path, os.O_RDWR, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	if err := lockFile(f, true); err != nil {
+		return err
+	}
+	defer func() { _ = unlockFile(f) }()
+
+	hdr, err := readHistoryHeader(f)
+	if err != nil {
+		return err
+	}
+	if hdr.capacity != h.capacity {
+		return ErrHistoryCorrupt
+	}
+
+	// 按环形缓冲区连续写入，到末尾时分段
+	buf := make([]byte, historyRecordSize*len(samples))
+	for i, s := range samples {
+		s.encode(buf[i*historyRecordSize:])
+	}
+	for len(buf) > 0 {
+		n := min(uint64(len(buf)/historyRecordSize), hdr.capacity-hdr.head)
+		off := int64(historyHeaderSize + hdr.head*historyRecordSize)
+		if _, err := f.WriteAt(buf[:n*historyRecordSize], off); err != nil {
+			return err
+		}
+		buf = buf[n*historyRecordSize:]
+		hdr.head = (hdr.head + n) % hdr.capacity
+		hdr.count = min(hdr.count+n, hdr.capacity)
+	}
+	return writeHistoryHeader(f, hdr)
+}
+
+// historyChunk Query 每次读取的记录数
+const historyChunk = 4096
+
+// Query 按时间顺序返回 since 之后且满足 match 的采样
+// match 为 nil 时返回全部。记录按写入顺序排列，先二分查找时间窗口的起点，
+// 再分块读取，不会把整个环形文件读进内存
+func (h *HistoryStore) Query(since time.Time, match func(HistorySample) bool) ([]HistorySample, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	f, err := os.Open(h.path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	if err := lockFile(f, false); err != nil {
+		return nil, err
+	}
+	defer func() { _ = unlockFile(f) }()
+
+	hdr, err := readHistoryHeader(f)
+	if err != nil {
+		return nil, err
+	}
+	if h.retention > 0 {
+		if cutoff := time.Now().Add(-h.retention); since.Before(cutoff) {
+			since = cutoff
+		}
+	}
+
+	// 最旧的记录在 head (写满后) 或 0 (未写满)
+	start := uint64(0)
+	if hdr.count == hdr.capacity {
+		start = hdr.head
+	}
+	offset := func(i uint64) int64 {
+		return int64(historyHeaderSize + (start+i)%hdr.capacity*historyRecordSize)
+	}
+
+	// 第一条不早于 since 的记录；多个实例交替写入时时间戳可能略有交错，下面仍逐条过滤
+	var readErr error
+	first := sort.Search(int(hdr.count), func(i int) bool {
+		var ts [8]byte
+		if _, err := f.ReadAt(ts[:], offset(uint64(i))); err != nil {
+			readErr = err
+			return true
+		}
+		return int64(binary.LittleEndian.Uint64(ts[:])) >= since.UnixMilli()
+	})
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	var out []HistorySample
+	buf := make([]byte, historyChunk*historyRecordSize)
+	for i := uint64(first); i < hdr.count; {
+		// 一次读到块的末尾或环形缓冲区的末尾
+		idx := (start + i) % hdr.capacity
+		n := min(hdr.count-i, historyChunk, hdr.capacity-idx)
+		data := buf[:n*historyRecordSize]
+		if _, err := f.ReadAt(data, offset(i)); err != nil && err != io.EOF {
+			return nil, err
+		}
+		for j := uint64(0); j < n; j++ {
+			s := decodeSample(data[j*historyRecordSize : (j+1)*historyRecordSize])
+			if s.Time.Before(since) {
+				continue
+			}
+			if match == nil || match(s) {
+				out = append(out, s)
+			}
+		}
+		i += n
+	}
+	return out, nil
+}
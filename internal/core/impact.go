@@ -0,0 +1,100 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Impact 向一个进程发送信号之前的影响预估，用于确认框
+type Impact struct {
+	Process     Process
+	Descendants int     // 所有子孙进程的数量
+	Verdict     Verdict // 保护检查的结果
+	Warnings    []string
+}
+
+// editorNames 可能有未保存内容的编辑器、IDE 与办公软件
+var editorNames = map[string]bool{
+	"vim": true, "nvim": true, "vi": true, "emacs": true, "nano": true, "micro": true, "hx": true, "kak": true,
+	"code": true, "code-oss": true, "cursor": true, "subl": true, "sublime_text": true, "zed": true,
+	"idea": true, "pycharm": true, "goland": true, "clion": true, "webstorm": true, "studio": true,
+	"gedit": true, "gnome-text-editor": true, "kate": true, "kwrite": true, "mousepad": true,
+	"soffice.bin": true, "libreoffice": true, "textedit": true, "xcode": true,
+	"notepad.exe": true, "notepad++.exe": true, "winword.exe": true, "excel.exe": true, "powerpnt.exe": true,
+}
+
+// databaseNames 强制杀掉可能需要较长的恢复时间，应优先使用 SIGTERM
+var databaseNames = map[string]bool{
+	"postgres": true, "postmaster": true, "mysqld": true, "mariadbd": true, "mongod": true,
+	"redis-server": true, "etcd": true, "elasticsearch": true, "clickhouse-server": true,
+}
+
+// multiplexerNames 终端复用器，杀掉会关闭其中所有的会话
+var multiplexerNames = map[string]bool{"tmux": true, "tmux: server": true, "screen": true, "zellij": true}
+
+var shellNames = map[string]bool{"bash": true, "zsh": true, "fish": true, "sh": true, "dash": true, "ksh": true, "tcsh": true}
+
+// Descendants 按最近一次扫描的结果返回 pid 的所有子孙进程，按层次从近到远排列
+func (s *Service) Descendants(pid int32) []Process {
+	s.mu.Lock()
+	children := make(map[int32][]Process)
+	for _, p := range s.known {
+		if p.PPID != p.PID {
+			children[p.PPID] = append(children[p.PPID], p)
+		}
+	}
+	s.mu.Unlock()
+
+	var out []Process
+	seen := map[int32]bool{pid: true}
+	queue := []int32{pid}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		kids := children[cur]
+		sort.Slice(kids, func(i, j int) bool { return kids[i].PID < kids[j].PID })
+		for _, k := range kids {
+			if seen[k.PID] {
+				continue
+			}
+			seen[k.PID] = true
+			out = append(out, k)
+			queue = append(queue, k.PID)
+		}
+	}
+	return out
+}
+
+// AssessImpact 汇总确认框需要展示的信息：子孙进程数、保护检查结果，以及可能丢失数据的提示
+func (s *Service) AssessImpact(p Process) Impact {
+	im := Impact{
+		Process:     p,
+		Descendants: len(s.Descendants(p.PID)),
+		Verdict:     s.Classify(p),
+	}
+	lower := strings.ToLower(p.Name)
+
+	// 保护检查已经给出的原因不再重复
+	if p.User == "root" && !strings.Contains(im.Verdict.Reason(), "owned by root") {
+		im.Warnings = append(im.Warnings, "owned by root")
+	}
+	if editorNames[lower] {
+		im.Warnings = append(im.Warnings, "editor: may have unsaved changes")
+	}
+	if databaseNames[lower] {
+		im.Warnings = append(im.Warnings, "database: prefer SIGTERM for a clean shutdown")
+	}
+	if multiplexerNames[lower] {
+		im.Warnings = append(im.Warnings, "terminal multiplexer: all its sessions will be closed")
+	}
+	if shellNames[lower] && im.Descendants > 0 {
+		if info, err := s.provider.GetSessionInfo(p.PID); err == nil && info.TTY != 0 {
+			im.Warnings = append(im.Warnings, fmt.Sprintf("interactive shell with %d running jobs", im.Descendants))
+		}
+	}
+	if p.HasIO && p.IOWriteRate > 0 {
+		im.Warnings = append(im.Warnings, fmt.Sprintf("writing to disk (%s/s)", FormatBytes(uint64(p.IOWriteRate))))
+	}
+	return im
+}
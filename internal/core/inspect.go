@@ -0,0 +1,123 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OpenFile 描述进程持有的一个文件描述符
+type OpenFile struct {
+	Fd      int
+	Type    string // file, socket, pipe, anon_inode, memfd...
+	Path    string
+	Offset  int64
+	Size    int64  // 仅对普通文件有意义
+	Device  uint64 // Device + Inode 用于识别多个进程共享的同一个文件
+	Inode   uint64
+	Deleted bool // 文件已被 unlink 但仍被进程持有
+}
+
+// DeletedFile 描述一个“已删除但仍被打开”的文件及其持有者
+// 同一进程通过多个 fd 持有同一个 inode 时只有一条记录
+type DeletedFile struct {
+	Holder Process
+	File   OpenFile // 持有者打开该 inode 的第一个 fd
+	Fds    []int    // 持有者打开该 inode 的全部 fd
+
+	// Holders 持有该 inode 的进程数；大于 1 时杀掉这一个持有者并不能释放空间
+	Holders int
+	// HolderTotal 该持有者持有的全部已删除文件的大小 (按 inode 去重)
+	HolderTotal uint64
+}
+
+// Limit 对应 /proc/<pid>/limits 中的一行
+type Limit struct {
+	Resource string
+	Soft     string
+	Hard     string
+	Unit     string
+}
+
+// Thread 描述进程下的一个线程
+type Thread struct {
+	TID     int32
+	Name    string
+	State   string
+	CPUTime float64 // 累计 CPU 时间 (秒)，百分比由调用方按时间差计算
+}
+
+// 进程引用文件的方式
+const (
+	RefFd   = "fd"
+	RefCwd  = "cwd"
+	RefRoot = "root"
+	RefExe  = "exe"
+	RefMmap = "mmap"
+)
+
+// FileRef 描述进程以何种方式引用了文件系统中的某个路径
+type FileRef struct {
+	Kind string // RefFd / RefCwd / RefRoot / RefExe / RefMmap
+	Fd   int    // 仅 Kind == RefFd 时有效
+	Path string
+}
+
+func (r FileRef) String() string {
+	if r.Kind == RefFd {
+		return fmt.Sprintf("fd %d → %s", r.Fd, r.Path)
+	}
+	return fmt.Sprintf("%s → %s", r.Kind, r.Path)
+}
+
+// Holder 描述一个占用了目标路径的进程
+type Holder struct {
+	Process Process
+	Refs    []FileRef
+}
+
+// How 汇总占用方式，例如 "cwd, fd, mmap"
+func (h Holder) How() string {
+	seen := make(map[string]bool)
+	var kinds []string
+	for _, r := range h.Refs {
+		if !seen[r.Kind] {
+			seen[r.Kind] = true
+			kinds = append(kinds, r.Kind)
+		}
+	}
+	return strings.Join(kinds, ", ")
+}
+
+// MemoryMap 对应 /proc/<pid>/maps 中的一行
+type MemoryMap struct {
+	Start   uint64
+	End     uint64
+	Perms   string // 例如 r-xp
+	Offset  uint64
+	Path    string // 匿名映射为空，特殊区域为 [heap]、[stack] 等
+	Deleted bool   // 映射的文件已被替换或删除 (例如升级后的旧 .so)
+}
+
+// Size 返回映射区域的大小
+func (m MemoryMap) Size() uint64 { return m.End - m.Start }
+
+// LibraryUser 描述一个映射了目标库的进程
+type LibraryUser struct {
+	Process   Process
+	Libraries []MemoryMap // 匹配到的映射 (同一文件只保留一条)
+}
+
+// ResourceCounts 进程占用的线程与文件描述符数量
+// 线程数总能读到；fd 目录需要权限，读不到时 HasFDs 为 false
+type ResourceCounts struct {
+	Threads int
+	FDs     int
+	HasFDs  bool
+}
+
+// SessionInfo 进程所属的会话与控制终端，用于判断误杀风险
+type SessionInfo struct {
+	SID    int32 // 会话 ID，等于 PID 时是会话首进程 (登录 shell、getty 等)
+	TTY    int32 // 控制终端的设备号，0 表示没有终端
+	Kernel bool  // 内核线程
+}
@@ -1,10 +1,48 @@
 package core
 
+import (
+	"syscall"
+	"time"
+)
+
 type Provider interface {
 	ListProcesses() ([]Process, error)
+	GetSystemStats() (SystemStats, error)
 	Kill(pid int32, force bool) error
 	Suspend(pid int32) error
 	Resume(pid int32) error
 	GetCreateTime(pid int32) (int64, error)
 	GetConnections(pid int32) ([]Connection, error)
+	Signal(pid int32, sig syscall.Signal) error
+
+	// 深度检视 (DetailView 各 Tab 按需加载)
+	GetEnviron(pid int32) ([]string, error)
+	GetOpenFiles(pid int32) ([]OpenFile, error)
+	GetLimits(pid int32) ([]Limit, error)
+	GetThreads(pid int32) ([]Thread, error)
+
+	// GetFileRefs 返回进程引用的所有路径：fd、cwd、root、exe 以及 mmap 的文件
+	GetFileRefs(pid int32) ([]FileRef, error)
+	GetMemoryMaps(pid int32) ([]MemoryMap, error)
+	GetMemoryDetail(pid int32) (MemoryDetail, error)
+	GetResourceCounts(pid int32) (ResourceCounts, error)
+	GetSessionInfo(pid int32) (SessionInfo, error)
+
+	// 调度优先级：nice 值与 I/O 调度类别 (ionice)
+	SetNice(pid int32, nice int) error
+	GetIOPriority(pid int32) (IOPriority, error)
+	SetIOPriority(pid int32, prio IOPriority) error
+
+	// CPU 亲和性；GetCpuset 返回进程所在 cgroup 实际可用的 CPU
+	GetAffinity(pid int32) (CPUSet, error)
+	SetAffinity(pid int32, cpus CPUSet, threads bool) error
+	GetCpuset(pid int32) (CPUSet, error)
+	OnlineCPUs() (CPUSet, error)
+}
+
+// RecordedProvider 由回放录制数据的 Provider 实现
+// 录制时 CPU 占用率与 I/O 速率已经算好，Service 不再根据差值重新计算；Now 返回回放位置对应的录制时间
+type RecordedProvider interface {
+	Provider
+	Now() time.Time
 }
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"errors"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FindLibraryUsers 找出映射了指定库的进程
+// name 按文件名做不区分大小写的子串匹配 (例如 "libssl" 匹配 libssl.so.3)
+// deletedOnly 为 true 时只关心已删除的库：打过补丁后仍在使用旧版本的进程需要重启
+// name 为空且 deletedOnly 为 true 时，列出所有映射了已删除库的进程
+func (s *Service) FindLibraryUsers(name string, deletedOnly bool) (users []LibraryUser, skipped int, err error) {
+	procs, err := s.GetProcesses()
+	if err != nil {
+		return nil, 0, err
+	}
+	name = strings.ToLower(name)
+
+	for _, p := range procs {
+		maps, err := s.provider.GetMemoryMaps(p.PID)
+		if err != nil {
+			if errors.Is(err, ErrPermissionDenied) {
+				skipped++
+			}
+			continue
+		}
+
+		seen := make(map[string]bool)
+		var matched []MemoryMap
+		for _, m := range maps {
+			if !strings.HasPrefix(m.Path, "/") || seen[m.Path] {
+				continue
+			}
+			base := strings.ToLower(filepath.Base(m.Path))
+			if deletedOnly && !(m.Deleted && isSharedLibrary(base)) {
+				continue
+			}
+			if name != "" && !strings.Contains(base, name) {
+				continue
+			}
+			seen[m.Path] = true
+			matched = append(matched, m)
+		}
+		if len(matched) > 0 {
+			users = append(users, LibraryUser{Process: p, Libraries: matched})
+		}
+	}
+
+	sort.Slice(users, func(i, j int) bool { return users[i].Process.PID < users[j].Process.PID })
+	return users, skipped, nil
+}
+
+// isSharedLibrary 粗略判断文件名是否是共享库 (libfoo.so / libfoo.so.1.2)
+func isSharedLibrary(base string) bool {
+	return strings.HasSuffix(base, ".so") || strings.Contains(base, ".so.")
+}
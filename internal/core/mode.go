@@ -0,0 +1,71 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Mode 决定 Service 是否真的向进程发送信号
+type Mode int
+
+const (
+	ModeNormal   Mode = iota
+	ModeReadOnly      // 只观察：所有会改变进程状态的操作都被拒绝
+	ModeDryRun        // 演练：照常走完确认流程，但只报告将要做什么
+)
+
+var (
+	// ErrReadOnly 只读模式下拒绝的操作
+	ErrReadOnly = errors.New("read-only mode: actions are disabled")
+	// ErrDryRun 演练模式下被拦截的操作，错误信息是 "dry run: would ..."
+	ErrDryRun = errors.New("dry run")
+)
+
+func (m Mode) String() string {
+	switch m {
+	case ModeReadOnly:
+		return "read-only"
+	case ModeDryRun:
+		return "dry-run"
+	default:
+		return "normal"
+	}
+}
+
+// SetMode 切换只读 / 演练模式，对 TUI、命令、控制 socket 和 API 同时生效
+func (s *Service) SetMode(m Mode) {
+	s.mu.Lock()
+	s.mode = m
+	s.mu.Unlock()
+}
+
+// Mode 当前模式；回放录制文件时总是只读
+func (s *Service) Mode() Mode {
+	if s.IsReplay() {
+		return ModeReadOnly
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mode
+}
+
+// authorize 每个会改变进程状态的操作都先经过这里：
+// 只读模式直接拒绝；然后是误杀保护 (resume 为 true 时豁免)；
+// 演练模式最后拦截，返回描述将要执行的操作的 ErrDryRun
+func (s *Service) authorize(pid int32, action string, resume bool) error {
+	mode := s.Mode()
+	if mode == ModeReadOnly {
+		return ErrReadOnly
+	}
+	if err := s.checkGuard(pid, resume); err != nil {
+		return err
+	}
+	if mode == ModeDryRun {
+		name := "unknown"
+		if p, ok := s.Lookup(pid); ok {
+			name = p.Name
+		}
+		return fmt.Errorf("%w: would %s %d (%s)", ErrDryRun, action, pid, name)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// nice 值的范围，数值越大优先级越低
+const (
+	MinNice = -20
+	MaxNice = 19
+)
+
+// IOClass I/O 调度类别，取值与 Linux ioprio 一致
+type IOClass int
+
+const (
+	IOClassNone IOClass = iota // 未设置，按 nice 值推算 best-effort 级别
+	IOClassRealtime
+	IOClassBestEffort
+	IOClassIdle
+)
+
+// MaxIOLevel realtime 与 best-effort 的级别为 0 (最高) 到 7 (最低)
+const MaxIOLevel = 7
+
+// IOPriority 进程的 I/O 调度类别与级别；Idle 与 None 没有级别
+type IOPriority struct {
+	Class IOClass
+	Level int
+}
+
+// String "none"、"rt/0"、"be/4"、"idle"，也是 ParseIOPriority 接受的格式
+func (p IOPriority) String() string {
+	switch p.Class {
+	case IOClassRealtime:
+		return fmt.Sprintf("rt/%d", p.Level)
+	case IOClassBestEffort:
+		return fmt.Sprintf("be/%d", p.Level)
+	case IOClassIdle:
+		return "idle"
+	default:
+		return "none"
+	}
+}
+
+// ParseIOPriority 解析 "idle"、"be"、"be/7"、"rt/0" (也接受 ionice 的数字类别 1/2/3)
+// 不写级别时为 4，与 ionice 的默认值一致
+func ParseIOPriority(s string) (IOPriority, error) {
+	name, level, hasLevel := strings.Cut(strings.ToLower(strings.TrimSpace(s)), "/")
+	var p IOPriority
+	switch name {
+	case "none", "0":
+		p.Class = IOClassNone
+	case "rt", "realtime", "1":
+		p.Class = IOClassRealtime
+	case "be", "best-effort", "2":
+		p.Class = IOClassBestEffort
+	case "idle", "3":
+		p.Class = IOClassIdle
+	default:
+		return p, fmt.Errorf("unknown I/O class %q (none, rt, be, idle)", name)
+	}
+	if p.Class == IOClassNone || p.Class == IOClassIdle {
+		if hasLevel {
+			return p, fmt.Errorf("I/O class %s takes no level", name)
+		}
+		return p, nil
+	}
+	p.Level = 4
+	if hasLevel {
+		n, err := strconv.Atoi(level)
+		if err != nil || n < 0 || n > MaxIOLevel {
+			return p, fmt.Errorf("invalid I/O level %q (0-%d)", level, MaxIOLevel)
+		}
+		p.Level = n
+	}
+	return p, nil
+}
+
+// ParseNice 解析并检查 nice 值的范围
+func ParseNice(s string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid nice value %q", s)
+	}
+	if n < MinNice || n > MaxNice {
+		return 0, fmt.Errorf("nice must be between %d and %d", MinNice, MaxNice)
+	}
+	return n, nil
+}
+
+// Renice 修改进程的 nice 值 (Linux 上对所有线程生效)
+// 不经过误杀保护：降低优先级不会丢数据，但只读与演练模式照常拦截
+func (s *Service) Renice(pid int32, nice int) (err error) {
+	if nice < MinNice || nice > MaxNice {
+		return fmt.Errorf("nice must be between %d and %d", MinNice, MaxNice)
+	}
+	done := s.begin(pid, "renice", "", fmt.Sprintf("nice=%d", nice))
+	defer func() { done(err) }()
+	if err := s.authorize(pid, fmt.Sprintf("renice to %d", nice), true); err != nil {
+		return err
+	}
+	return priorityError(s.provider.SetNice(pid, nice), "renice", "another user's process")
+}
+
+func (s *Service) GetIOPriority(pid int32) (IOPriority, error) {
+	return s.provider.GetIOPriority(pid)
+}
+
+// SetIOPriority 修改进程的 I/O 调度类别与级别，与 Renice 一样不经过误杀保护
+func (s *Service) SetIOPriority(pid int32, prio IOPriority) (err error) {
+	done := s.begin(pid, "ionice", "", "ioprio="+prio.String())
+	defer func() { done(err) }()
+	if err := s.authorize(pid, "set I/O priority "+prio.String()+" on", true); err != nil {
+		return err
+	}
+	return priorityError(s.provider.SetIOPriority(pid, prio), "ionice", "another user's process or the realtime class")
+}
+
+// priorityError 把 EPERM / EACCES 说清楚：调高优先级、使用 realtime 类别或修改其他用户的进程
+// 都需要 root 或 CAP_SYS_NICE；eperm 说明 EPERM 对该操作意味着什么。
+// 仍然包装 ErrPermissionDenied，方便 API 返回 403
+func priorityError(err error, action, eperm string) error {
+	switch {
+	case errors.Is(err, syscall.EACCES):
+		// setpriority: 没有 CAP_SYS_NICE 时只能调低优先级 (增大 nice)
+		return fmt.Errorf("%w: %s: raising priority needs root or CAP_SYS_NICE", ErrPermissionDenied, action)
+	case errors.Is(err, syscall.EPERM):
+		return fmt.Errorf("%w: %s: %s needs root or CAP_SYS_NICE", ErrPermissionDenied, action, eperm)
+	case errors.Is(err, syscall.ESRCH):
+		return fmt.Errorf("%s: %w", action, err)
+	}
+	return err
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -27,10 +28,63 @@ type Process struct {
 	TreePrefix string
 
 	Cmdline     string
-	MemoryUsage uint64
-	CpuPercent  float64
+	MemoryUsage uint64  // RSS
+	CpuPercent  float64 // 由 Service 根据 CPUTime 的差值计算
+	CPUTime     float64 // 累计 CPU 时间 (user + system，秒)
 	User        string
 	CreateTime  int64
+
+	// 精确内存统计 (来自 smaps_rollup)，代价较高，只为可见行或按需采集
+	// HasMemDetail 为 false 时以下字段无意义
+	HasMemDetail bool
+	PSS          uint64
+	USS          uint64
+	Swap         uint64
+	SharedMem    uint64
+
+	// 磁盘 I/O (来自 /proc/<pid>/io)，无权限读取时 HasIO 为 false，界面显示为不可用而不是 0
+	HasIO        bool
+	IOReadBytes  uint64  // 累计从存储设备读取的字节数
+	IOWriteBytes uint64  // 累计写入存储设备的字节数
+	IOReadOps    uint64  // 累计 read 类系统调用次数
+	IOWriteOps   uint64  // 累计 write 类系统调用次数
+	IOReadRate   float64 // 字节/秒，由 Service 根据两次扫描的差值计算
+	IOWriteRate  float64
+
+	// 调度优先级 (来自 /proc/<pid>/stat 与 ioprio_get)，当前平台读不到时 HasPriority 为 false
+	HasPriority bool
+	Nice        int32
+	Priority    int32 // 内核优先级，普通进程为 20 + nice，实时进程为负数
+	IOPriority  IOPriority
+
+	// CPU 亲和性 (主线程)，当前平台读不到时为 nil
+	Affinity CPUSet
+
+	// 进程目录中的说明与处理方式，由 Service 在扫描时填入，未收录时为 nil
+	Catalog *CatalogEntry
+}
+
+// IORate 读写速率之和
+func (p Process) IORate() float64 {
+	return p.IOReadRate + p.IOWriteRate
+}
+
+// MemoryDetail 是一次 smaps_rollup 采样的结果
+type MemoryDetail struct {
+	RSS    uint64
+	PSS    uint64 // 按共享进程数均摊后的内存
+	USS    uint64 // 进程独占的内存，杀掉后能真正释放的部分
+	Swap   uint64
+	Shared uint64
+}
+
+// ApplyMemoryDetail 把采样结果写入 Process
+func (p *Process) ApplyMemoryDetail(d MemoryDetail) {
+	p.HasMemDetail = true
+	p.PSS = d.PSS
+	p.USS = d.USS
+	p.Swap = d.Swap
+	p.SharedMem = d.Shared
 }
 
 func (p Process) FilterValue() string {
@@ -40,7 +94,11 @@ func (p Process) FilterValue() string {
 	}
 	portsStr := strings.Join(ports, " ")
 
-	return fmt.Sprintf("%s %d %s %s", p.Name, p.PID, portsStr, p.Status)
+	value := fmt.Sprintf("%s %d %s %s", p.Name, p.PID, portsStr, p.Status)
+	if p.Catalog != nil {
+		value += " " + p.Catalog.Owner
+	}
+	return value
 }
 
 func (p Process) IsSuspended() bool {
@@ -67,10 +125,20 @@ func (p Process) Title() string {
 		if p.IsSuspended() {
 			nameDisplay += " [PAUSED]"
 		}
+		if p.Catalog != nil {
+			nameDisplay += " " + p.Catalog.Badge()
+		}
 
 		basic := fmt.Sprintf("%s%s%s", p.TreePrefix, statusIcon, nameDisplay)
-		stats := fmt.Sprintf("  (PID:%d | %.1f%% | %.0fMB)", p.PID, p.CpuPercent, memMB)
-		return basic + stats
+		stats := fmt.Sprintf("PID:%d | %.1f%% | %.0fMB", p.PID, p.CpuPercent, memMB)
+		if p.HasMemDetail {
+			stats += fmt.Sprintf(" | PSS %.0fMB", toMB(p.PSS))
+		}
+		// 树状视图一行放不下太多，只标出调整过优先级的进程
+		if p.HasPriority && p.Nice != 0 {
+			stats += fmt.Sprintf(" | NI %d", p.Nice)
+		}
+		return basic + "  (" + stats + ")"
 	}
 
 	// ---------------------------------------------------------
@@ -95,6 +163,9 @@ func (p Process) Title() string {
 	if p.IsSuspended() {
 		displayName = fmt.Sprintf("[PAUSED] %s", p.Name)
 	}
+	if p.Catalog != nil {
+		displayName += " " + p.Catalog.Badge()
+	}
 
 	return fmt.Sprintf("%s%s %s", statusIcon, displayName, portStr)
 }
@@ -109,8 +180,49 @@ func (p Process) Description() string {
 	memMB := float64(p.MemoryUsage) / 1024 / 1024
 
 	// 这里加了 Status 字段显示
-	return fmt.Sprintf("PID: %d | CPU: %.1f%% | Mem: %.1f MB",
-		p.PID, p.CpuPercent, memMB)
+	desc := fmt.Sprintf("PID: %d | CPU: %.1f%% | Mem: %.1f MB | NI: %s | PRI: %s | CPUs: %s",
+		p.PID, p.CpuPercent, memMB, p.NiceString(), p.PriorityString(), p.AffinityString())
+	if p.HasIO {
+		desc += fmt.Sprintf(" | IO: R %s/s W %s/s", FormatBytes(uint64(p.IOReadRate)), FormatBytes(uint64(p.IOWriteRate)))
+	} else {
+		desc += " | IO: n/a"
+	}
+	if p.HasMemDetail {
+		desc += fmt.Sprintf(" | PSS: %.1f MB | USS: %.1f MB | Swap: %.1f MB | Shr: %.1f MB",
+			toMB(p.PSS), toMB(p.USS), toMB(p.Swap), toMB(p.SharedMem))
+	}
+	return desc
+}
+
+// NiceString 列表中 NI 列的内容，读不到时为 "-"
+func (p Process) NiceString() string {
+	if !p.HasPriority {
+		return "-"
+	}
+	return strconv.Itoa(int(p.Nice))
+}
+
+// PriorityString 列表中 PRI 列的内容，与 top 一样最高的实时优先级显示为 "rt"
+func (p Process) PriorityString() string {
+	switch {
+	case !p.HasPriority:
+		return "-"
+	case p.Priority <= -100:
+		return "rt"
+	}
+	return strconv.Itoa(int(p.Priority))
+}
+
+// AffinityString 列表中 CPUs 列的内容，例如 "0-3,8"；读不到时为 "-"
+func (p Process) AffinityString() string {
+	if p.Affinity == nil {
+		return "-"
+	}
+	return p.Affinity.String()
+}
+
+func toMB(b uint64) float64 {
+	return float64(b) / 1024 / 1024
 }
 
 func (p Process) ShortCmd() string {
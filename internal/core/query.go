@@ -0,0 +1,145 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ProcessQuery /select 等命令使用的查询条件，各条件之间是 "且" 的关系，零值匹配所有进程
+type ProcessQuery struct {
+	Words   []string // 不带 = 的词：进程名或命令行包含 (不区分大小写)
+	Name    string   // 进程名完全相同 (不区分大小写)
+	User    string
+	Cmdline string // 命令行包含 (不区分大小写)
+	Status  string // 状态前缀，例如 T 表示已暂停
+	Port    int    // 监听该端口
+	Owner   string // 进程目录中的负责团队
+	PPID    int32
+	MinCPU  float64 // cpu>N，单位为百分比
+	MinMem  uint64  // mem>N，RSS，单位为字节
+}
+
+// ParseProcessQuery 解析 "nginx user=www port=80 cpu>50 mem>1G" 形式的查询
+func ParseProcessQuery(args []string) (ProcessQuery, error) {
+	var q ProcessQuery
+	for _, arg := range args {
+		if k, v, ok := strings.Cut(arg, ">"); ok {
+			switch k {
+			case "cpu":
+				n, err := strconv.ParseFloat(strings.TrimSuffix(v, "%"), 64)
+				if err != nil {
+					return q, fmt.Errorf("invalid cpu %q", v)
+				}
+				q.MinCPU = n
+			case "mem":
+				n, err := ParseSize(v)
+				if err != nil {
+					return q, err
+				}
+				q.MinMem = n
+			default:
+				return q, fmt.Errorf("unknown comparison %q (cpu>N, mem>SIZE)", k)
+			}
+			continue
+		}
+		k, v, ok := strings.Cut(arg, "=")
+		if !ok {
+			q.Words = append(q.Words, strings.ToLower(arg))
+			continue
+		}
+		switch k {
+		case "name":
+			q.Name = v
+		case "user":
+			q.User = v
+		case "cmd", "cmdline":
+			q.Cmdline = strings.ToLower(v)
+		case "status", "state":
+			q.Status = strings.ToUpper(v)
+		case "owner":
+			q.Owner = v
+		case "port", "ppid":
+			n, err := strconv.ParseInt(v, 10, 32)
+			if err != nil {
+				return q, fmt.Errorf("invalid %s %q", k, v)
+			}
+			if k == "port" {
+				q.Port = int(n)
+			} else {
+				q.PPID = int32(n)
+			}
+		default:
+			return q, fmt.Errorf("unknown field %q (name, user, cmd, status, port, owner, ppid, cpu>N, mem>SIZE)", k)
+		}
+	}
+	return q, nil
+}
+
+// Match 判断进程是否满足全部条件
+func (q ProcessQuery) Match(p Process) bool {
+	switch {
+	case q.Name != "" && !strings.EqualFold(p.Name, q.Name):
+		return false
+	case q.User != "" && p.User != q.User:
+		return false
+	case q.Cmdline != "" && !strings.Contains(strings.ToLower(p.Cmdline), q.Cmdline):
+		return false
+	case q.Status != "" && !strings.HasPrefix(strings.ToUpper(p.Status), q.Status):
+		return false
+	case q.PPID != 0 && p.PPID != q.PPID:
+		return false
+	case q.MinCPU > 0 && p.CpuPercent <= q.MinCPU:
+		return false
+	case q.MinMem > 0 && p.MemoryUsage <= q.MinMem:
+		return false
+	case q.Owner != "" && (p.Catalog == nil || !strings.EqualFold(p.Catalog.Owner, q.Owner)):
+		return false
+	}
+	if q.Port != 0 && !containsPort(p.Ports, q.Port) {
+		return false
+	}
+	name, cmdline := strings.ToLower(p.Name), strings.ToLower(p.Cmdline)
+	for _, w := range q.Words {
+		if !strings.Contains(name, w) && !strings.Contains(cmdline, w) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsPort(ports []int, port int) bool {
+	for _, p := range ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSize 解析 512K、100M、1.5G 这样的大小 (1024 进制)，不带单位时为字节
+func ParseSize(s string) (uint64, error) {
+	t := strings.ToUpper(strings.TrimSpace(s))
+	t = strings.TrimSuffix(strings.TrimSuffix(t, "B"), "I")
+	mult := 1.0
+	if t != "" {
+		switch t[len(t)-1] {
+		case 'K':
+			mult = 1 << 10
+		case 'M':
+			mult = 1 << 20
+		case 'G':
+			mult = 1 << 30
+		case 'T':
+			mult = 1 << 40
+		}
+		if mult > 1 {
+			t = t[:len(t)-1]
+		}
+	}
+	n, err := strconv.ParseFloat(t, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return uint64(n * mult), nil
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrHistoryDisabled 未配置历史文件
+var ErrHistoryDisabled = errors.New("history recording is disabled")
+
+// recorder 把扫描结果按固定间隔写入历史文件
+// 界面本身在扫描时会顺带喂给它；界面停止扫描 (例如停留在帮助页) 时由后台协程补扫
+type recorder struct {
+	store    *HistoryStore
+	interval time.Duration
+
+	mu    sync.Mutex
+	last  time.Time
+	queue chan []HistorySample
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+func newRecorder(store *HistoryStore, interval time.Duration) *recorder {
+	return &recorder{
+		store:    store,
+		interval: interval,
+		queue:    make(chan []HistorySample, 4),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// observe 在距离上次记录超过 interval 时把这一轮扫描放入写入队列
+func (r *recorder) observe(procs []Process, now time.Time) {
+	r.mu.Lock()
+	if !r.elapsed(now) {
+		r.mu.Unlock()
+		return
+	}
+	r.last = now
+	r.mu.Unlock()
+
+	samples := make([]HistorySample, len(procs))
+	for i, p := range procs {
+		samples[i] = SampleFromProcess(p, now)
+	}
+	// 写入跟不上时丢弃，不能阻塞扫描
+	select {
+	case r.queue <- samples:
+	default:
+	}
+}
+
+func (r *recorder) due(now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.elapsed(now)
+}
+
+// elapsed 留出 10% 的余量，避免定时器抖动导致每隔一轮才记录一次
+func (r *recorder) elapsed(now time.Time) bool {
+	return now.Sub(r.last) >= r.interval-r.interval/10
+}
+
+// run 后台协程：负责写盘，并在没有人扫描时主动扫描
+func (r *recorder) run(scan func()) {
+	defer close(r.done)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case samples := <-r.queue:
+			_ = r.store.Append(samples)
+		case now := <-ticker.C:
+			if r.due(now) {
+				go scan()
+			}
+		case <-r.stop:
+			// 把已排队的数据写完再退出
+			for {
+				select {
+				case samples := <-r.queue:
+					_ = r.store.Append(samples)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// SetHistoryStore 设置历史文件，之后可通过 History 查询
+func (s *Service) SetHistoryStore(store *HistoryStore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.history = store
+}
+
+// StartRecorder 开始在后台按 interval 记录所有进程的采样
+func (s *Service) StartRecorder(interval time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.history == nil {
+		return ErrHistoryDisabled
+	}
+	if s.rec != nil {
+		return nil
+	}
+	s.rec = newRecorder(s.history, interval)
+	go s.rec.run(func() { _, _ = s.GetProcesses() })
+	return nil
+}
+
+// StopRecorder 停止后台记录并写完队列中的数据
+func (s *Service) StopRecorder() {
+	s.mu.Lock()
+	r := s.rec
+	s.rec = nil
+	s.mu.Unlock()
+	if r != nil {
+		close(r.stop)
+		<-r.done
+	}
+}
+
+// History 查询 since 之后满足 match 的历史采样
+func (s *Service) History(since time.Time, match func(HistorySample) bool) ([]HistorySample, error) {
+	s.mu.Lock()
+	store := s.history
+	s.mu.Unlock()
+	if store == nil {
+		return nil, ErrHistoryDisabled
+	}
+	return store.Query(since, match)
+}
+
+// ProcessHistory 某个进程 (按 PID + 创建时间识别) 的历史采样
+func (s *Service) ProcessHistory(pid int32, createTime int64, since time.Time) ([]HistorySample, error) {
+	return s.History(since, func(h HistorySample) bool {
+		return h.PID == pid && h.CreateTime == createTime
+	})
+}
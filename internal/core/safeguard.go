@@ -0,0 +1,363 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// GuardLevel 发送信号前对目标进程的风险分级
+type GuardLevel int
+
+const (
+	GuardNone    GuardLevel = iota // 普通进程
+	GuardConfirm                   // 需要输入进程名确认
+	GuardRefuse                    // 直接拒绝
+)
+
+var (
+	// ErrProtected 目标是系统关键进程，拒绝操作
+	ErrProtected = errors.New("process is protected")
+	// ErrNeedsConfirm 目标有误杀风险，需要先调用 Service.Confirm
+	ErrNeedsConfirm = errors.New("confirmation required")
+)
+
+// confirmTTL 输入确认后的有效期，足够完成一次 "先 TERM 再 KILL"
+const confirmTTL = time.Minute
+
+// criticalNames 杀掉后系统会直接崩溃或登出的进程，一律拒绝
+var criticalNames = map[string]bool{
+	"kernel_task":  true,
+	"system":       true,
+	"smss.exe":     true,
+	"csrss.exe":    true,
+	"wininit.exe":  true,
+	"winlogon.exe": true,
+	"services.exe": true,
+	"lsass.exe":    true,
+}
+
+// defaultProtectedNames 远程登录、会话总线和图形界面，误杀会断开当前会话，需要确认
+var defaultProtectedNames = []string{
+	"sshd", "systemd", "dbus-daemon", "dbus-broker",
+	"Xorg", "Xwayland", "gdm", "gdm3", "sddm", "lightdm",
+	"gnome-shell", "kwin_wayland", "kwin_x11", "plasmashell", "sway", "Hyprland",
+	"WindowServer", "loginwindow", "explorer.exe", "dwm.exe",
+}
+
+// Verdict 一个进程的分级结果及原因
+type Verdict struct {
+	Level   GuardLevel
+	Reasons []string
+}
+
+func (v *Verdict) add(level GuardLevel, reason string) {
+	if level > v.Level {
+		v.Level = level
+	}
+	v.Reasons = append(v.Reasons, reason)
+}
+
+// Reason 所有原因，用于确认框和错误信息
+func (v Verdict) Reason() string {
+	return strings.Join(v.Reasons, "; ")
+}
+
+// SafeguardError 被保护机制拦下的操作
+type SafeguardError struct {
+	PID     int32
+	Name    string
+	Verdict Verdict
+}
+
+func (e *SafeguardError) Error() string {
+	if e.Verdict.Level == GuardRefuse {
+		return fmt.Sprintf("refusing to signal %d (%s): %s", e.PID, e.Name, e.Verdict.Reason())
+	}
+	return fmt.Sprintf("%d (%s) needs confirmation: %s", e.PID, e.Name, e.Verdict.Reason())
+}
+
+func (e *SafeguardError) Unwrap() error {
+	if e.Verdict.Level == GuardRefuse {
+		return ErrProtected
+	}
+	return ErrNeedsConfirm
+}
+
+// SafeguardOptions 用户可配置的保护名单，内置名单之外追加
+type SafeguardOptions struct {
+	Disabled  bool
+	Names     []string // 进程名，不区分大小写
+	Users     []string
+	Cmdlines  []string // 命令行正则
+	Refuse    bool     // 名单内的进程直接拒绝，而不是要求确认
+	AllowRoot bool     // 以 root 运行时不再对 root 的进程要求确认
+}
+
+type safeguards struct {
+	opts      SafeguardOptions
+	names     map[string]bool // 小写进程名 → 是否由用户配置 (内置名单为 false)
+	users     map[string]bool
+	cmdlines  []*regexp.Regexp
+	confirmed map[procKey]confirmGrant
+
+	self      SessionInfo // quell 自己的会话与终端
+	selfKnown bool
+}
+
+func newSafeguards(opts SafeguardOptions) (*safeguards, error) {
+	g := &safeguards{
+		opts:      opts,
+		names:     make(map[string]bool),
+		users:     make(map[string]bool),
+		confirmed: make(map[procKey]confirmGrant),
+	}
+	for _, n := range defaultProtectedNames {
+		g.names[strings.ToLower(n)] = false
+	}
+	for _, n := range opts.Names {
+		g.names[strings.ToLower(n)] = true
+	}
+	for _, u := range opts.Users {
+		g.users[u] = true
+	}
+	for _, expr := range opts.Cmdlines {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("protected cmdline %q: %w", expr, err)
+		}
+		g.cmdlines = append(g.cmdlines, re)
+	}
+	return g, nil
+}
+
+// SetSafeguards 替换保护名单，正则有误时保持原设置
+func (s *Service) SetSafeguards(opts SafeguardOptions) error {
+	g, err := newSafeguards(opts)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.guard = g
+	s.mu.Unlock()
+	return nil
+}
+
+// Classify 判断对 p 发送信号 (SIGCONT 除外) 的风险
+func (s *Service) Classify(p Process) Verdict {
+	s.mu.Lock()
+	g := s.guard
+	s.mu.Unlock()
+
+	var v Verdict
+	if g.opts.Disabled {
+		return v
+	}
+	lower := strings.ToLower(p.Name)
+
+	// 1. 系统关键进程：init、内核线程、Windows 核心服务
+	info, infoErr := s.provider.GetSessionInfo(p.PID)
+	switch {
+	case runtime.GOOS == "windows" && (p.PID == 0 || p.PID == 4):
+		v.add(GuardRefuse, "system process")
+	case runtime.GOOS != "windows" && p.PID == 1:
+		v.add(GuardRefuse, "init process")
+	case infoErr == nil && info.Kernel:
+		v.add(GuardRefuse, "kernel thread")
+	case criticalNames[lower]:
+		v.add(GuardRefuse, "critical system process")
+	}
+
+	// 2. 进程目录中约定的处理方式；safe 表示负责人确认可以随时结束，
+	// 只免去下面按 root 身份和内置名单推测出的确认，用户配置的保护名单、系统关键进程和 quell 自身的检查照旧
+	safe := false
+	if e := s.CatalogFor(p); e != nil {
+		who := ""
+		if e.Owner != "" {
+			who = " (owner: " + e.Owner + ")"
+		}
+		switch e.Policy {
+		case PolicyNever:
+			v.add(GuardRefuse, "catalog: never kill"+who)
+		case PolicyWarn:
+			reason := "catalog: " + e.Description
+			if e.Description == "" {
+				reason = "catalog: warn"
+			}
+			v.add(GuardConfirm, reason+who)
+		case PolicySafe:
+			safe = true
+		}
+	}
+
+	// 3. quell 自己以及它所在的 shell、终端
+	self := int32(os.Getpid())
+	if p.PID == self {
+		v.add(GuardConfirm, "this quell instance")
+	} else if s.isAncestor(p.PID, self) {
+		v.add(GuardConfirm, "ancestor of quell (your shell or terminal)")
+	}
+
+	// 4. 其他终端的会话首进程 (别人的登录 shell、getty)
+	own, ownOK := s.selfSession(g)
+	if infoErr == nil && ownOK && info.SID == p.PID && info.TTY != 0 && info.TTY != own.TTY {
+		v.add(GuardConfirm, "session leader of another terminal")
+	}
+
+	// 5. 以 root 运行时，root 的进程多是系统服务；当前终端里启动的除外
+	inOwnSession := infoErr == nil && ownOK && info.SID == own.SID
+	if !safe && !g.opts.AllowRoot && os.Geteuid() == 0 && p.User == "root" && p.PID != self && !inOwnSession {
+		v.add(GuardConfirm, "owned by root")
+	}
+
+	// 6. 保护名单；目录中标为 safe 的进程不再因内置名单要求确认
+	level := GuardConfirm
+	if g.opts.Refuse {
+		level = GuardRefuse
+	}
+	if configured, ok := g.names[lower]; ok && (configured || !safe || level == GuardRefuse) {
+		v.add(level, "protected name "+p.Name)
+	}
+	if g.users[p.User] {
+		v.add(level, "protected user "+p.User)
+	}
+	for _, re := range g.cmdlines {
+		if re.MatchString(p.Cmdline) {
+			v.add(level, "protected cmdline /"+re.String()+"/")
+			break
+		}
+	}
+	return v
+}
+
+// confirmGrant 一次输入确认：只对给出确认的界面 (surface) 在有效期内生效
+type confirmGrant struct {
+	expires time.Time
+	surface Surface
+}
+
+// Confirm 用户已输入确认，接下来一段时间内允许通过同一界面对这些进程发送信号；
+// 其他界面 (控制 socket、API) 仍需各自确认。以 PID + CreateTime 识别，PID 被复用后确认自动失效
+func (s *Service) Confirm(procs ...Process) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for k, g := range s.guard.confirmed {
+		if now.After(g.expires) {
+			delete(s.guard.confirmed, k)
+		}
+	}
+	for _, p := range procs {
+		s.guard.confirmed[procKey{p.PID, p.CreateTime}] = confirmGrant{expires: now.Add(confirmTTL), surface: s.surface}
+	}
+}
+
+// Confirmed 返回对 procs 免于确认的 Service，与 As 一样共享其余状态
+// 确认只属于返回的副本，随它一起丢弃，不会让 TUI、控制 socket 或其他 API 请求跳过确认；
+// 用于随请求一起给出确认的场景 (API 的 ?confirm=<进程名>)
+func (s *Service) Confirmed(procs ...Process) *Service {
+	c := *s
+	c.confirmed = make(map[procKey]bool, len(s.confirmed)+len(procs))
+	for k := range s.confirmed {
+		c.confirmed[k] = true
+	}
+	for _, p := range procs {
+		c.confirmed[procKey{p.PID, p.CreateTime}] = true
+	}
+	return &c
+}
+
+// checkGuard 在发送信号前调用；resume 为 true (SIGCONT) 时不受限制
+func (s *Service) checkGuard(pid int32, resume bool) error {
+	if resume {
+		return nil
+	}
+	p, ok := s.Lookup(pid)
+	if !ok {
+		// 进程不存在，交给 Provider 返回 ESRCH
+		return nil
+	}
+	v := s.Classify(p)
+	switch v.Level {
+	case GuardNone:
+		return nil
+	case GuardConfirm:
+		if s.confirmed[procKey{p.PID, p.CreateTime}] {
+			return nil
+		}
+		s.mu.Lock()
+		g, ok := s.guard.confirmed[procKey{p.PID, p.CreateTime}]
+		s.mu.Unlock()
+		if ok && g.surface == s.surface && time.Now().Before(g.expires) {
+			return nil
+		}
+	}
+	return &SafeguardError{PID: p.PID, Name: p.Name, Verdict: v}
+}
+
+// Lookup 按 PID 查找进程，优先使用最近一次扫描的结果，找不到时重新扫描
+func (s *Service) Lookup(pid int32) (Process, bool) {
+	s.mu.Lock()
+	p, ok := s.known[pid]
+	s.mu.Unlock()
+	if ok {
+		return p, true
+	}
+	procs, err := s.provider.ListProcesses()
+	if err != nil {
+		return Process{}, false
+	}
+	s.remember(procs)
+	for _, p := range procs {
+		if p.PID == pid {
+			return p, true
+		}
+	}
+	return Process{}, false
+}
+
+// remember 缓存一次扫描结果，供保护检查查询名字、用户与父子关系
+func (s *Service) remember(procs []Process) {
+	known := make(map[int32]Process, len(procs))
+	for _, p := range procs {
+		known[p.PID] = p
+	}
+	s.mu.Lock()
+	s.known = known
+	s.mu.Unlock()
+}
+
+// isAncestor pid 是否在 self 的父进程链上
+func (s *Service) isAncestor(pid, self int32) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for cur, depth := self, 0; depth < 64; depth++ {
+		p, ok := s.known[cur]
+		if !ok || p.PPID == cur || p.PPID <= 0 {
+			return false
+		}
+		if p.PPID == pid {
+			return true
+		}
+		cur = p.PPID
+	}
+	return false
+}
+
+// selfSession quell 自己的会话信息，只读取一次
+func (s *Service) selfSession(g *safeguards) (SessionInfo, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !g.selfKnown {
+		info, err := s.provider.GetSessionInfo(int32(os.Getpid()))
+		if err != nil {
+			return SessionInfo{}, false
+		}
+		g.self, g.selfKnown = info, true
+	}
+	return g.self, true
+}
@@ -0,0 +1,50 @@
+package core
+
+import "testing"
+
+// sessionlessProvider 读不到会话信息，Classify 只按名单与目录判断
+type sessionlessProvider struct{ Provider }
+
+func (sessionlessProvider) GetSessionInfo(pid int32) (SessionInfo, error) {
+	return SessionInfo{}, ErrNotSupported
+}
+
+func TestClassifySafeCatalogEntry(t *testing.T) {
+	entries, err := ParseCatalog("- name: worker\n  policy: safe\n- name: sshd\n  policy: safe\n- name: lsass.exe\n  policy: safe\n", "team.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalog := &Catalog{byID: make(map[string]*CatalogEntry)}
+	if err := catalog.Add(entries); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		opts      SafeguardOptions
+		proc      Process
+		wantLevel GuardLevel
+		wantWhy   string
+	}{
+		{"built-in name waived", SafeguardOptions{}, Process{PID: 100, Name: "sshd"}, GuardNone, ""},
+		{"configured name kept", SafeguardOptions{Names: []string{"Worker"}}, Process{PID: 100, Name: "worker"}, GuardConfirm, "protected name worker"},
+		{"configured user kept", SafeguardOptions{Users: []string{"db"}}, Process{PID: 100, Name: "worker", User: "db"}, GuardConfirm, "protected user db"},
+		{"configured cmdline kept", SafeguardOptions{Cmdlines: []string{"--prod"}}, Process{PID: 100, Name: "worker", Cmdline: "worker --prod"}, GuardConfirm, "protected cmdline /--prod/"},
+		{"refuse mode keeps built-in names", SafeguardOptions{Refuse: true}, Process{PID: 100, Name: "sshd"}, GuardRefuse, "protected name sshd"},
+		{"critical process still refused", SafeguardOptions{}, Process{PID: 100, Name: "lsass.exe"}, GuardRefuse, "critical system process"},
+		{"not in catalog", SafeguardOptions{}, Process{PID: 100, Name: "systemd"}, GuardConfirm, "protected name systemd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(sessionlessProvider{})
+			svc.SetCatalog(catalog)
+			if err := svc.SetSafeguards(tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			v := svc.Classify(tt.proc)
+			if v.Level != tt.wantLevel || v.Reason() != tt.wantWhy {
+				t.Errorf("Classify = %d %q, want %d %q", v.Level, v.Reason(), tt.wantLevel, tt.wantWhy)
+			}
+		})
+	}
+}
@@ -2,19 +2,68 @@ package core
 
 import (
 	"sync"
+	"syscall"
+	"time"
 )
 
+// Service 是 TUI、命令行和 API 共用的业务层
+// 状态放在共享的 serviceState 中，As 返回的视图只是多了一个来源标记，用于审计日志
 type Service struct {
+	*serviceState
+	surface   Surface
+	confirmed map[procKey]bool // 只对这个副本生效的确认，见 Confirmed
+}
+
+type serviceState struct {
 	provider   Provider
 	mu         sync.Mutex
 	pausedPids map[int32]int64
+	acct       *accountant
+	history    *HistoryStore
+	rec        *recorder
+	guard      *safeguards
+	mode       Mode
+	known      map[int32]Process // 最近一次扫描结果，供保护检查使用
+	audit      *AuditLog
+	catalog    *Catalog
 }
 
 func NewService(p Provider) *Service {
-	return &Service{
+	guard, _ := newSafeguards(SafeguardOptions{})
+	return &Service{serviceState: &serviceState{
 		provider:   p,
 		pausedPids: make(map[int32]int64),
+		acct:       newAccountant(),
+		guard:      guard,
+	}}
+}
+
+// As 返回共享同一状态的 Service，其发起的操作在审计日志中记为 surface
+func (s *Service) As(surface Surface) *Service {
+	return &Service{serviceState: s.serviceState, surface: surface}
+}
+
+// Now 数据对应的时刻：回放时是回放位置的录制时间，否则是当前时间
+// 图表与采样都应使用它，回放时才会落在正确的时间轴上
+func (s *Service) Now() time.Time {
+	if rp, ok := s.provider.(RecordedProvider); ok {
+		return rp.Now()
 	}
+	return time.Now()
+}
+
+// Surface 这个 Service 发起的操作在审计日志中记录的来源
+func (s *Service) Surface() Surface { return s.surface }
+
+// IsReplay 数据是否来自录制文件
+func (s *Service) IsReplay() bool {
+	_, ok := s.provider.(RecordedProvider)
+	return ok
+}
+
+// SetCPUMode 设置 CPU 百分比的归一化方式 (irix / solaris)
+func (s *Service) SetCPUMode(mode CPUMode) {
+	s.acct.setMode(mode)
 }
 
 // GetProcesses 获取进程列表
@@ -23,15 +72,26 @@ func (s *Service) GetProcesses() ([]Process, error) {
 	if err != nil {
 		return nil, err
 	}
+	now := s.Now()
+	if !s.IsReplay() {
+		s.acct.apply(procs, now)
+	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.rec != nil {
+		s.rec.observe(procs, now)
+	}
+
 	alivePids := make(map[int32]bool)
+	known := make(map[int32]Process, len(procs))
 
 	for i := range procs {
 		pid := procs[i].PID
 		alivePids[pid] = true
+		procs[i].Catalog = s.catalog.Lookup(procs[i])
+		known[pid] = procs[i]
 
 		// 🔥 核心校验：只有 PID 相同 且 创建时间相同，才认为是“那个被暂停的进程”
 		if savedTime, ok := s.pausedPids[pid]; ok {
@@ -50,14 +110,24 @@ func (s *Service) GetProcesses() ([]Process, error) {
 			delete(s.pausedPids, pid)
 		}
 	}
+	s.known = known
 	return procs, nil
 }
 
 // Kill 终止进程
-func (s *Service) Kill(pid int32, force bool) error {
+func (s *Service) Kill(pid int32, force bool) (err error) {
 	// 如果进程被杀，理论上 GetProcesses 的清理逻辑会处理，
 	// 但为了保险，这里也可以直接移除
-	err := s.provider.Kill(pid, force)
+	action, sig := "send SIGTERM to", "SIGTERM"
+	if force {
+		action, sig = "send SIGKILL to", "SIGKILL"
+	}
+	done := s.begin(pid, "kill", sig, "")
+	defer func() { done(err) }()
+	if err := s.authorize(pid, action, false); err != nil {
+		return err
+	}
+	err = s.provider.Kill(pid, force)
 	if err == nil {
 		s.mu.Lock()
 		delete(s.pausedPids, pid)
@@ -66,8 +136,13 @@ func (s *Service) Kill(pid int32, force bool) error {
 	return err
 }
 
-func (s *Service) Suspend(pid int32) error {
-	err := s.provider.Suspend(pid)
+func (s *Service) Suspend(pid int32) (err error) {
+	done := s.begin(pid, "suspend", "", "")
+	defer func() { done(err) }()
+	if err := s.authorize(pid, "suspend", false); err != nil {
+		return err
+	}
+	err = s.provider.Suspend(pid)
 	if err == nil {
 		// 🔥 暂停成功后，获取该进程的“身份证” (CreateTime)
 		ct, ctErr := s.provider.GetCreateTime(pid)
@@ -80,8 +155,13 @@ func (s *Service) Suspend(pid int32) error {
 	return err
 }
 
-func (s *Service) Resume(pid int32) error {
-	err := s.provider.Resume(pid)
+func (s *Service) Resume(pid int32) (err error) {
+	done := s.begin(pid, "resume", "", "")
+	defer func() { done(err) }()
+	if err := s.authorize(pid, "resume", true); err != nil {
+		return err
+	}
+	err = s.provider.Resume(pid)
 	if err == nil {
 		// 🔥 成功恢复后，移出名单
 		s.mu.Lock()
@@ -137,3 +217,62 @@ func (s *Service) GetPausedProcs() []struct {
 func (s *Service) GetConnections(pid int32) ([]Connection, error) {
 	return s.provider.GetConnections(pid)
 }
+
+func (s *Service) GetEnviron(pid int32) ([]string, error) {
+	return s.provider.GetEnviron(pid)
+}
+
+func (s *Service) GetOpenFiles(pid int32) ([]OpenFile, error) {
+	return s.provider.GetOpenFiles(pid)
+}
+
+func (s *Service) GetLimits(pid int32) ([]Limit, error) {
+	return s.provider.GetLimits(pid)
+}
+
+func (s *Service) GetThreads(pid int32) ([]Thread, error) {
+	return s.provider.GetThreads(pid)
+}
+
+// GetResourceCounts 线程数与 fd 数
+func (s *Service) GetResourceCounts(pid int32) (ResourceCounts, error) {
+	return s.provider.GetResourceCounts(pid)
+}
+
+// Signal 向进程发送任意信号 (例如 SIGHUP 让守护进程重新打开日志)
+// SIGCONT 不受保护机制限制，其余信号与 Kill 一样先检查
+func (s *Service) Signal(pid int32, sig syscall.Signal) (err error) {
+	name := SignalName(sig)
+	done := s.begin(pid, "signal", name, "")
+	defer func() { done(err) }()
+	if err := s.authorize(pid, "send "+name+" to", name == "SIGCONT"); err != nil {
+		return err
+	}
+	return s.provider.Signal(pid, sig)
+}
+
+func (s *Service) GetMemoryMaps(pid int32) ([]MemoryMap, error) {
+	return s.provider.GetMemoryMaps(pid)
+}
+
+func (s *Service) GetMemoryDetail(pid int32) (MemoryDetail, error) {
+	return s.provider.GetMemoryDetail(pid)
+}
+
+// FillMemoryDetail 为 procs 中的进程补充 PSS/USS/Swap/Shared
+// pids 为 nil 时处理全部进程，否则只处理 pids 中的进程 (通常是屏幕上可见的行)
+func (s *Service) FillMemoryDetail(procs []Process, pids map[int32]bool) {
+	for i := range procs {
+		if pids != nil && !pids[procs[i].PID] {
+			continue
+		}
+		if d, err := s.provider.GetMemoryDetail(procs[i].PID); err == nil {
+			procs[i].ApplyMemoryDetail(d)
+		}
+	}
+}
+
+// GetSystemStats 获取整机概况
+func (s *Service) GetSystemStats() (SystemStats, error) {
+	return s.provider.GetSystemStats()
+}
@@ -0,0 +1,42 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// ParseSignal 解析信号名或编号：TERM、SIGTERM、sigterm、15 均可
+func ParseSignal(s string) (syscall.Signal, error) {
+	name := strings.ToUpper(strings.TrimPrefix(strings.TrimSpace(s), "-"))
+	name = strings.TrimPrefix(name, "SIG")
+	if sig, ok := signalNames[name]; ok {
+		return sig, nil
+	}
+	if n, err := strconv.Atoi(name); err == nil && n > 0 {
+		return syscall.Signal(n), nil
+	}
+	return 0, fmt.Errorf("unknown signal %q", s)
+}
+
+// SignalName 返回 "SIGTERM" 这样的名字，未知信号返回编号
+func SignalName(sig syscall.Signal) string {
+	for name, s := range signalNames {
+		if s == sig {
+			return "SIG" + name
+		}
+	}
+	return strconv.Itoa(int(sig))
+}
+
+// SignalNames 当前平台支持按名字发送的信号 (不带 SIG 前缀)，按编号排序
+func SignalNames() []string {
+	names := make([]string, 0, len(signalNames))
+	for name := range signalNames {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return signalNames[names[i]] < signalNames[names[j]] })
+	return names
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"strings"
+	"time"
+)
+
+// SystemStats 整机概况，用于列表页顶部的摘要
+type SystemStats struct {
+	Load1, Load5, Load15 float64
+	Uptime               time.Duration
+	PerCPU               []float64 // 每个逻辑核心的占用率 (0-100)
+	MemTotal             uint64
+	MemUsed              uint64
+	SwapTotal            uint64
+	SwapUsed             uint64
+}
+
+// TaskCounts 按状态统计的进程数
+type TaskCounts struct {
+	Total    int
+	Running  int
+	Sleeping int
+	Stopped  int
+	Zombie   int
+}
+
+// CountTasks 根据 Status 字段统计各状态的进程数
+func CountTasks(procs []Process) TaskCounts {
+	c := TaskCounts{Total: len(procs)}
+	for _, p := range procs {
+		if p.IsSuspended() {
+			c.Stopped++
+			continue
+		}
+		switch strings.ToLower(p.Status) {
+		case "running", "r":
+			c.Running++
+		case "zombie", "z":
+			c.Zombie++
+		case "sleep", "idle", "wait", "lock", "blocked", "s", "i", "d":
+			c.Sleeping++
+		}
+	}
+	return c
+}
@@ -0,0 +1,61 @@
+package core
+
+import (
+	"errors"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// WhoHas 找出所有占用了 path 的进程
+// path 可以是文件、目录或挂载点：目录下任意路径被引用都算占用
+// skipped 为因权限不足无法检查的进程数
+func (s *Service) WhoHas(path string) (holders []Holder, skipped int, err error) {
+	target, err := filepath.Abs(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	// 统一解析符号链接，否则 /var/run 与 /run 之类的路径匹配不上
+	if resolved, err := filepath.EvalSymlinks(target); err == nil {
+		target = resolved
+	}
+
+	procs, err := s.GetProcesses()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, p := range procs {
+		refs, err := s.provider.GetFileRefs(p.PID)
+		if err != nil {
+			if errors.Is(err, ErrPermissionDenied) {
+				skipped++
+			}
+			continue
+		}
+
+		var matched []FileRef
+		for _, r := range refs {
+			if pathWithin(r.Path, target) {
+				matched = append(matched, r)
+			}
+		}
+		if len(matched) > 0 {
+			holders = append(holders, Holder{Process: p, Refs: matched})
+		}
+	}
+
+	sort.Slice(holders, func(i, j int) bool { return holders[i].Process.PID < holders[j].Process.PID })
+	return holders, skipped, nil
+}
+
+// pathWithin 判断 p 是否等于 target 或位于 target 目录之下
+func pathWithin(p, target string) bool {
+	if p == target {
+		return true
+	}
+	if target == "/" {
+		return strings.HasPrefix(p, "/")
+	}
+	return strings.HasPrefix(p, target+"/")
+}
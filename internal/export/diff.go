@@ -0,0 +1,220 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Microindole/quell/internal/core"
+)
+
+// Thresholds 判断 CPU / 内存变化是否值得报告
+type Thresholds struct {
+	CPU      float64 // CPU 百分点
+	RSSBytes uint64  // RSS 变化的绝对值下限
+	RSSRatio float64 // RSS 变化的相对比例下限 (0.5 = 50%)
+}
+
+var DefaultThresholds = Thresholds{CPU: 20, RSSBytes: 50 << 20, RSSRatio: 0.5}
+
+// Change 前后两次快照中同一个进程的变化
+type Change struct {
+	Old, New     Record
+	ByCmdline    bool // 通过命令行匹配 (进程被重启，PID 变了)
+	PortsAdded   []int
+	PortsRemoved []int
+	CPUChanged   bool
+	RSSChanged   bool
+}
+
+// Report diff 的结果
+type Report struct {
+	New     []Record
+	Gone    []Record
+	Changed []Change
+}
+
+type identity struct {
+	pid        int32
+	createTime int64
+}
+
+// Diff 比较两份导出
+// 先按 (PID, CreateTime) 匹配；剩余的按完全相同的命令行配对，视为同一服务被重启
+func Diff(a, b *Document, th Thresholds) Report {
+	before, after := a.Flatten(), b.Flatten()
+	comparePorts := a.HasColumn("ports") && b.HasColumn("ports")
+
+	byID := make(map[identity]int, len(before))
+	for i, r := range before {
+		byID[identity{r.PID, r.CreateTime}] = i
+	}
+
+	var rep Report
+	matched := make([]bool, len(before))
+	var unmatched []Record
+	for _, r := range after {
+		if i, ok := byID[identity{r.PID, r.CreateTime}]; ok {
+			matched[i] = true
+			if c, changed := compare(before[i], r, comparePorts, th); changed {
+				rep.Changed = append(rep.Changed, c)
+			}
+			continue
+		}
+		unmatched = append(unmatched, r)
+	}
+
+	// 命令行兜底匹配
+	byCmd := make(map[string][]int)
+	for i, r := range before {
+		if !matched[i] && r.Cmdline != "" {
+			byCmd[r.Cmdline] = append(byCmd[r.Cmdline], i)
+		}
+	}
+	for _, r := range unmatched {
+		if cands := byCmd[r.Cmdline]; r.Cmdline != "" && len(cands) > 0 {
+			i := cands[0]
+			byCmd[r.Cmdline] = cands[1:]
+			matched[i] = true
+			c, _ := compare(before[i], r, comparePorts, th)
+			c.ByCmdline = true
+			rep.Changed = append(rep.Changed, c)
+			continue
+		}
+		rep.New = append(rep.New, r)
+	}
+	for i, r := range before {
+		if !matched[i] {
+			rep.Gone = append(rep.Gone, r)
+		}
+	}
+
+	sortRecords(rep.New)
+	sortRecords(rep.Gone)
+	sort.Slice(rep.Changed, func(i, j int) bool { return rep.Changed[i].New.PID < rep.Changed[j].New.PID })
+	return rep
+}
+
+func sortRecords(rs []Record) {
+	sort.Slice(rs, func(i, j int) bool { return rs[i].PID < rs[j].PID })
+}
+
+func compare(old, cur Record, comparePorts bool, th Thresholds) (Change, bool) {
+	c := Change{Old: old, New: cur}
+	if comparePorts {
+		c.PortsAdded, c.PortsRemoved = portDiff(old.Ports, cur.Ports)
+	}
+	if old.CPU != nil && cur.CPU != nil {
+		c.CPUChanged = math.Abs(*cur.CPU-*old.CPU) >= th.CPU
+	}
+	if old.RSS != nil && cur.RSS != nil {
+		lo, hi := *old.RSS, *cur.RSS
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		delta := hi - lo
+		c.RSSChanged = delta >= th.RSSBytes && (lo == 0 || float64(delta)/float64(lo) >= th.RSSRatio)
+	}
+	changed := len(c.PortsAdded) > 0 || len(c.PortsRemoved) > 0 || c.CPUChanged || c.RSSChanged
+	return c, changed
+}
+
+func portDiff(old, cur []int) (added, removed []int) {
+	had := make(map[int]bool, len(old))
+	for _, p := range old {
+		had[p] = true
+	}
+	has := make(map[int]bool, len(cur))
+	for _, p := range cur {
+		has[p] = true
+		if !had[p] {
+			added = append(added, p)
+		}
+	}
+	for _, p := range old {
+		if !has[p] {
+			removed = append(removed, p)
+		}
+	}
+	return added, removed
+}
+
+// Empty 两份快照之间没有值得报告的变化
+func (r Report) Empty() bool {
+	return len(r.New) == 0 && len(r.Gone) == 0 && len(r.Changed) == 0
+}
+
+// WriteText 以文本形式输出报告
+func (r Report) WriteText(w io.Writer) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d new, %d gone, %d changed\n", len(r.New), len(r.Gone), len(r.Changed))
+
+	if len(r.New) > 0 {
+		sb.WriteString("\nNew processes:\n")
+		for _, p := range r.New {
+			fmt.Fprintf(&sb, "  + %s\n", describe(p))
+		}
+	}
+	if len(r.Gone) > 0 {
+		sb.WriteString("\nGone processes:\n")
+		for _, p := range r.Gone {
+			fmt.Fprintf(&sb, "  - %s\n", describe(p))
+		}
+	}
+	if len(r.Changed) > 0 {
+		sb.WriteString("\nChanged:\n")
+		for _, c := range r.Changed {
+			fmt.Fprintf(&sb, "  ~ %s: %s\n", describe(c.New), strings.Join(c.details(), "; "))
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (c Change) details() []string {
+	var out []string
+	if c.ByCmdline {
+		out = append(out, fmt.Sprintf("restarted (was PID %d)", c.Old.PID))
+	}
+	if len(c.PortsAdded) > 0 || len(c.PortsRemoved) > 0 {
+		var parts []string
+		for _, p := range c.PortsAdded {
+			parts = append(parts, "+"+strconv.Itoa(p))
+		}
+		for _, p := range c.PortsRemoved {
+			parts = append(parts, "-"+strconv.Itoa(p))
+		}
+		out = append(out, "ports "+strings.Join(parts, " "))
+	}
+	if c.CPUChanged {
+		out = append(out, fmt.Sprintf("cpu %.1f%% -> %.1f%%", *c.Old.CPU, *c.New.CPU))
+	}
+	if c.RSSChanged {
+		out = append(out, fmt.Sprintf("rss %s -> %s", core.FormatBytes(*c.Old.RSS), core.FormatBytes(*c.New.RSS)))
+	}
+	if len(out) == 0 {
+		out = append(out, "no significant change")
+	}
+	return out
+}
+
+func describe(r Record) string {
+	s := fmt.Sprintf("%d %s", r.PID, r.Name)
+	if r.User != "" {
+		s += " (" + r.User + ")"
+	}
+	if len(r.Ports) > 0 {
+		s += " ports " + joinPorts(r.Ports, ",")
+	}
+	if r.Cmdline != "" {
+		cmd := r.Cmdline
+		if len(cmd) > 60 {
+			cmd = cmd[:57] + "..."
+		}
+		s += "  " + cmd
+	}
+	return s
+}
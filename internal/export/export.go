@@ -0,0 +1,463 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Microindole/quell/internal/core"
+)
+
+// Format 导出格式
+type Format string
+
+const (
+	FormatJSON     Format = "json"
+	FormatCSV      Format = "csv"
+	FormatMarkdown Format = "md"
+)
+
+// ParseFormat 解析格式名，接受 json / csv / md / markdown
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "json":
+		return FormatJSON, nil
+	case "csv":
+		return FormatCSV, nil
+	case "md", "markdown":
+		return FormatMarkdown, nil
+	}
+	return "", fmt.Errorf("unknown format %q (json, csv or md)", s)
+}
+
+// FormatFromPath 根据扩展名推断格式，无法识别时使用 JSON
+func FormatFromPath(path string) Format {
+	if f, err := ParseFormat(strings.TrimPrefix(filepath.Ext(path), ".")); err == nil {
+		return f
+	}
+	return FormatJSON
+}
+
+// Record 一个进程的导出数据
+// 数值字段使用指针，以区分 "未导出该列" 与 "值为 0"
+type Record struct {
+	PID        int32    `json:"pid"`
+	CreateTime int64    `json:"create_time"`
+	PPID       *int32   `json:"ppid,omitempty"`
+	User       string   `json:"user,omitempty"`
+	Name       string   `json:"name,omitempty"`
+	Status     string   `json:"status,omitempty"`
+	CPU        *float64 `json:"cpu,omitempty"`
+	RSS        *uint64  `json:"rss,omitempty"`
+	PSS        *uint64  `json:"pss,omitempty"`
+	USS        *uint64  `json:"uss,omitempty"`
+	IORead     *float64 `json:"io_read,omitempty"`
+	IOWrite    *float64 `json:"io_write,omitempty"`
+	Nice       *int32   `json:"nice,omitempty"`
+	Priority   *int32   `json:"pri,omitempty"`
+	Affinity   string   `json:"affinity,omitempty"`
+	Ports      []int    `json:"ports,omitempty"`
+	Cmdline    string   `json:"cmdline,omitempty"`
+	Children   []Record `json:"children,omitempty"`
+}
+
+// Document JSON 导出文件的结构，也是 diff 的输入
+type Document struct {
+	Time      time.Time `json:"time"`
+	Hostname  string    `json:"hostname"`
+	Columns   []string  `json:"columns"`
+	Tree      bool      `json:"tree"`
+	Processes []Record  `json:"processes"`
+}
+
+// HasColumn 文档是否导出了某一列
+func (d *Document) HasColumn(key string) bool {
+	for _, c := range d.Columns {
+		if c == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Flatten 展开树状结构，返回所有进程
+func (d *Document) Flatten() []Record {
+	var out []Record
+	var walk func([]Record)
+	walk = func(rs []Record) {
+		for _, r := range rs {
+			out = append(out, r)
+			walk(r.Children)
+		}
+	}
+	walk(d.Processes)
+	return out
+}
+
+// column 定义一列：表头、从进程取值写入 Record、以及文本格式的取值
+// raw 是 CSV 使用的未格式化数值，为空时与 text 相同
+type column struct {
+	title string
+	set   func(r *Record, p core.Process)
+	text  func(p core.Process) string
+	raw   func(p core.Process) string
+}
+
+var columns = map[string]column{
+	"pid": {title: "PID", set: func(r *Record, p core.Process) {},
+		text: func(p core.Process) string { return strconv.Itoa(int(p.PID)) }},
+	"ppid": {title: "PPID", set: func(r *Record, p core.Process) { r.PPID = &p.PPID },
+		text: func(p core.Process) string { return strconv.Itoa(int(p.PPID)) }},
+	"user": {title: "USER", set: func(r *Record, p core.Process) { r.User = p.User },
+		text: func(p core.Process) string { return p.User }},
+	"name": {title: "NAME", set: func(r *Record, p core.Process) { r.Name = p.Name },
+		text: func(p core.Process) string { return p.Name }},
+	"status": {title: "STATUS", set: func(r *Record, p core.Process) { r.Status = p.Status },
+		text: func(p core.Process) string { return p.Status }},
+	"cpu": {title: "CPU%", set: func(r *Record, p core.Process) { r.CPU = &p.CpuPercent },
+		text: func(p core.Process) string { return strconv.FormatFloat(p.CpuPercent, 'f', 1, 64) }},
+	"rss": {title: "RSS", set: func(r *Record, p core.Process) { r.RSS = &p.MemoryUsage },
+		text: func(p core.Process) string { return core.FormatBytes(p.MemoryUsage) },
+		raw:  func(p core.Process) string { return strconv.FormatUint(p.MemoryUsage, 10) }},
+	"pss": {title: "PSS", set: func(r *Record, p core.Process) {
+		if p.HasMemDetail {
+			r.PSS = &p.PSS
+		}
+	},
+		text: func(p core.Process) string { return memText(p, p.PSS, core.FormatBytes) },
+		raw:  func(p core.Process) string { return memText(p, p.PSS, formatUint) }},
+	"uss": {title: "USS", set: func(r *Record, p core.Process) {
+		if p.HasMemDetail {
+			r.USS = &p.USS
+		}
+	},
+		text: func(p core.Process) string { return memText(p, p.USS, core.FormatBytes) },
+		raw:  func(p core.Process) string { return memText(p, p.USS, formatUint) }},
+	"io_read": {title: "READ/s", set: func(r *Record, p core.Process) {
+		if p.HasIO {
+			r.IORead = &p.IOReadRate
+		}
+	},
+		text: func(p core.Process) string { return ioText(p, p.IOReadRate, formatRate) },
+		raw:  func(p core.Process) string { return ioText(p, p.IOReadRate, formatFloat) }},
+	"io_write": {title: "WRITE/s", set: func(r *Record, p core.Process) {
+		if p.HasIO {
+			r.IOWrite = &p.IOWriteRate
+		}
+	},
+		text: func(p core.Process) string { return ioText(p, p.IOWriteRate, formatRate) },
+		raw:  func(p core.Process) string { return ioText(p, p.IOWriteRate, formatFloat) }},
+	"nice": {title: "NI", set: func(r *Record, p core.Process) {
+		if p.HasPriority {
+			r.Nice = &p.Nice
+		}
+	},
+		text: func(p core.Process) string { return prioText(p, p.NiceString) }},
+	"pri": {title: "PRI", set: func(r *Record, p core.Process) {
+		if p.HasPriority {
+			r.Priority = &p.Priority
+		}
+	},
+		text: func(p core.Process) string { return prioText(p, p.PriorityString) }},
+	"affinity": {title: "CPUS", set: func(r *Record, p core.Process) { r.Affinity = p.Affinity.String() },
+		text: func(p core.Process) string {
+			if p.Affinity == nil {
+				return "n/a"
+			}
+			return p.Affinity.String()
+		}},
+	"ports": {title: "PORTS", set: func(r *Record, p core.Process) { r.Ports = p.Ports },
+		text: func(p core.Process) string { return joinPorts(p.Ports, ",") },
+		raw:  func(p core.Process) string { return joinPorts(p.Ports, " ") }},
+	"cmdline": {title: "COMMAND", set: func(r *Record, p core.Process) { r.Cmdline = p.Cmdline },
+		text: func(p core.Process) string { return p.Cmdline }},
+}
+
+func formatUint(v uint64) string   { return strconv.FormatUint(v, 10) }
+func formatFloat(v float64) string { return strconv.FormatFloat(v, 'f', 0, 64) }
+func formatRate(v float64) string  { return core.FormatBytes(uint64(v)) + "/s" }
+
+func joinPorts(ports []int, sep string) string {
+	ps := make([]string, len(ports))
+	for i, port := range ports {
+		ps[i] = strconv.Itoa(port)
+	}
+	return strings.Join(ps, sep)
+}
+
+// memText / prioText / ioText 在数据不可用时返回 n/a (CSV 中为空)，而不是 0
+func memText(p core.Process, v uint64, format func(uint64) string) string {
+	if !p.HasMemDetail {
+		return "n/a"
+	}
+	return format(v)
+}
+
+func prioText(p core.Process, format func() string) string {
+	if !p.HasPriority {
+		return "n/a"
+	}
+	return format()
+}
+
+func ioText(p core.Process, v float64, format func(float64) string) string {
+	if !p.HasIO {
+		return "n/a"
+	}
+	return format(v)
+}
+
+// NeedsMemoryDetail columns 中是否有 PSS/USS，需要导出前先为进程采集 smaps_rollup
+func NeedsMemoryDetail(columns []string) bool {
+	for _, c := range columns {
+		if c == "pss" || c == "uss" {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultColumns 未指定 --columns 时导出的列
+var DefaultColumns = []string{"pid", "ppid", "user", "name", "status", "cpu", "rss", "ports", "cmdline"}
+
+// ParseColumns 解析逗号分隔的列名，"all" 表示全部
+func ParseColumns(s string) ([]string, error) {
+	if s == "" {
+		return DefaultColumns, nil
+	}
+	if s == "all" {
+		return []string{"pid", "ppid", "user", "name", "status", "cpu", "rss", "pss", "uss",
+			"io_read", "io_write", "nice", "pri", "affinity", "ports", "cmdline"}, nil
+	}
+	var out []string
+	for _, c := range strings.Split(s, ",") {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if c == "" {
+			continue
+		}
+		if _, ok := columns[c]; !ok {
+			return nil, fmt.Errorf("unknown column %q", c)
+		}
+		out = append(out, c)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no columns selected")
+	}
+	return out, nil
+}
+
+// ColumnNames 所有可用的列名，用于帮助信息
+func ColumnNames() []string {
+	var names []string
+	for k := range columns {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Options 导出选项
+type Options struct {
+	Format  Format
+	Columns []string
+	Tree    bool // 按父子关系嵌套 (JSON) 或缩进 (CSV / Markdown)
+}
+
+// Write 把 procs 按 opts 写入 w，procs 的顺序即为导出顺序 (树状模式下为同级的顺序)
+func Write(w io.Writer, procs []core.Process, opts Options) error {
+	if len(opts.Columns) == 0 {
+		opts.Columns = DefaultColumns
+	}
+	recs := buildRecords(procs, opts)
+
+	switch opts.Format {
+	case FormatCSV:
+		return writeCSV(w, recs, opts)
+	case FormatMarkdown:
+		return writeMarkdown(w, recs, opts)
+	default:
+		host, _ := os.Hostname()
+		doc := Document{Time: time.Now(), Hostname: host, Columns: opts.Columns, Tree: opts.Tree}
+		if opts.Tree {
+			doc.Processes = nest(recs)
+		} else {
+			for _, r := range recs {
+				doc.Processes = append(doc.Processes, r.rec)
+			}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doc)
+	}
+}
+
+// WriteFile 导出到文件，Format 为空时根据扩展名推断
+func WriteFile(path string, procs []core.Process, opts Options) (err error) {
+	if opts.Format == "" {
+		opts.Format = FormatFromPath(path)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	return Write(f, procs, opts)
+}
+
+// Load 读取 JSON 导出文件
+func Load(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: not a quell JSON export: %w", path, err)
+	}
+	return &doc, nil
+}
+
+// entry 导出时的中间结果：Record 加上原始进程 (供文本格式取值)
+type entry struct {
+	rec   Record
+	proc  core.Process
+	depth int
+}
+
+// buildRecords 生成记录；树状模式下按深度优先顺序排列并记录深度
+func buildRecords(procs []core.Process, opts Options) []entry {
+	toEntry := func(p core.Process, depth int) entry {
+		r := Record{PID: p.PID, CreateTime: p.CreateTime}
+		for _, c := range opts.Columns {
+			columns[c].set(&r, p)
+		}
+		return entry{rec: r, proc: p, depth: depth}
+	}
+
+	if !opts.Tree {
+		out := make([]entry, len(procs))
+		for i, p := range procs {
+			out[i] = toEntry(p, 0)
+		}
+		return out
+	}
+
+	exists := make(map[int32]bool, len(procs))
+	for _, p := range procs {
+		exists[p.PID] = true
+	}
+	children := make(map[int32][]core.Process)
+	var roots []core.Process
+	for _, p := range procs {
+		if p.PPID == 0 || p.PPID == p.PID || !exists[p.PPID] {
+			roots = append(roots, p)
+		} else {
+			children[p.PPID] = append(children[p.PPID], p)
+		}
+	}
+
+	var out []entry
+	var walk func(ps []core.Process, depth int)
+	walk = func(ps []core.Process, depth int) {
+		for _, p := range ps {
+			out = append(out, toEntry(p, depth))
+			walk(children[p.PID], depth+1)
+		}
+	}
+	walk(roots, 0)
+	return out
+}
+
+// nest 把深度优先顺序的记录还原成嵌套结构
+func nest(entries []entry) []Record {
+	var build func(i, depth int) ([]Record, int)
+	build = func(i, depth int) ([]Record, int) {
+		var out []Record
+		for i < len(entries) && entries[i].depth == depth {
+			r := entries[i].rec
+			i++
+			if i < len(entries) && entries[i].depth > depth {
+				r.Children, i = build(i, depth+1)
+			}
+			out = append(out, r)
+		}
+		return out, i
+	}
+	recs, _ := build(0, 0)
+	return recs
+}
+
+func rowValues(e entry, opts Options, raw bool) []string {
+	row := make([]string, len(opts.Columns))
+	for i, c := range opts.Columns {
+		col := columns[c]
+		if raw && col.raw != nil {
+			row[i] = col.raw(e.proc)
+		} else {
+			row[i] = col.text(e.proc)
+		}
+		if c == "name" && opts.Tree && e.depth > 0 {
+			row[i] = strings.Repeat("  ", e.depth-1) + "└─ " + row[i]
+		}
+	}
+	return row
+}
+
+func writeCSV(w io.Writer, entries []entry, opts Options) error {
+	cw := csv.NewWriter(w)
+	header := append([]string(nil), opts.Columns...)
+	if opts.Tree {
+		header = append(header, "depth")
+	}
+	_ = cw.Write(header)
+	for _, e := range entries {
+		// CSV 中数值保持原样，方便表格软件处理
+		row := rowValues(e, opts, true)
+		for i := range row {
+			if row[i] == "n/a" {
+				row[i] = ""
+			}
+		}
+		if opts.Tree {
+			row = append(row, strconv.Itoa(e.depth))
+		}
+		_ = cw.Write(row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeMarkdown(w io.Writer, entries []entry, opts Options) error {
+	header := make([]string, len(opts.Columns))
+	sep := make([]string, len(opts.Columns))
+	for i, c := range opts.Columns {
+		header[i] = columns[c].title
+		sep[i] = "---"
+	}
+	host, _ := os.Hostname()
+	if _, err := fmt.Fprintf(w, "Processes on %s at %s\n\n| %s |\n| %s |\n", host,
+		time.Now().Format("2006-01-02 15:04:05"), strings.Join(header, " | "), strings.Join(sep, " | ")); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		row := rowValues(e, opts, false)
+		for i := range row {
+			row[i] = strings.ReplaceAll(row[i], "|", `\|`)
+		}
+		if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(row, " | ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
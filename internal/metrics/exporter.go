@@ -0,0 +1,289 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Microindole/quell/internal/config"
+	"github.com/Microindole/quell/internal/core"
+)
+
+// defaultMaxProcesses 单进程指标的默认上限，防止标签基数失控
+const defaultMaxProcesses = 200
+
+// otherGroup 未命中任何规则的进程所属的分组
+const otherGroup = "other"
+
+// cacheFor 多个抓取方同时抓取时复用同一次扫描
+const cacheFor = time.Second
+
+type group struct {
+	name    string
+	match   *regexp.Regexp
+	cmdline *regexp.Regexp
+}
+
+func (g group) matches(p core.Process) bool {
+	if g.match != nil && !g.match.MatchString(p.Name) {
+		return false
+	}
+	if g.cmdline != nil && !g.cmdline.MatchString(p.Cmdline) {
+		return false
+	}
+	return true
+}
+
+// Exporter 以 Prometheus 文本格式导出进程与分组指标
+type Exporter struct {
+	svc      *core.Service
+	groups   []group
+	maxProcs int
+
+	mu     sync.Mutex
+	last   time.Time
+	cached []byte
+}
+
+func NewExporter(svc *core.Service, cfg config.MetricsConfig) (*Exporter, error) {
+	e := &Exporter{svc: svc, maxProcs: cfg.MaxProcesses}
+	if e.maxProcs == 0 {
+		e.maxProcs = defaultMaxProcesses
+	}
+	for _, r := range cfg.Groups {
+		if r.Name == "" || (r.Match == "" && r.Cmdline == "") {
+			return nil, fmt.Errorf("metrics group %q needs a name and a match or cmdline pattern", r.Name)
+		}
+		g := group{name: r.Name}
+		var err error
+		if r.Match != "" {
+			if g.match, err = regexp.Compile(r.Match); err != nil {
+				return nil, fmt.Errorf("metrics group %q: %w", r.Name, err)
+			}
+		}
+		if r.Cmdline != "" {
+			if g.cmdline, err = regexp.Compile(r.Cmdline); err != nil {
+				return nil, fmt.Errorf("metrics group %q: %w", r.Name, err)
+			}
+		}
+		e.groups = append(e.groups, g)
+	}
+	return e, nil
+}
+
+func (e *Exporter) groupOf(p core.Process) string {
+	for _, g := range e.groups {
+		if g.matches(p) {
+			return g.name
+		}
+	}
+	return otherGroup
+}
+
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := e.scrape()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(body)
+}
+
+func (e *Exporter) scrape() ([]byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.cached != nil && time.Since(e.last) < cacheFor {
+		return e.cached, nil
+	}
+
+	start := time.Now()
+	procs, err := e.svc.GetProcesses()
+	if err != nil {
+		return nil, err
+	}
+	body := e.render(procs, start)
+	e.cached, e.last = body, time.Now()
+	return body, nil
+}
+
+// procStat 一次抓取中某个进程的全部数据
+type procStat struct {
+	core.Process
+	group  string
+	counts core.ResourceCounts
+}
+
+type groupStat struct {
+	procs   int
+	cpu     float64
+	cpuSecs float64
+	rss     uint64
+	threads int
+	fds     int
+	ports   map[int]bool
+}
+
+func (e *Exporter) render(procs []core.Process, start time.Time) []byte {
+	stats := make([]procStat, len(procs))
+	groups := make(map[string]*groupStat)
+	for _, g := range e.groups {
+		groups[g.name] = &groupStat{ports: map[int]bool{}}
+	}
+	groups[otherGroup] = &groupStat{ports: map[int]bool{}}
+
+	for i, p := range procs {
+		st := procStat{Process: p, group: e.groupOf(p)}
+		st.counts, _ = e.svc.GetResourceCounts(p.PID)
+		stats[i] = st
+
+		g := groups[st.group]
+		g.procs++
+		g.cpu += p.CpuPercent
+		g.cpuSecs += p.CPUTime
+		g.rss += p.MemoryUsage
+		g.threads += st.counts.Threads
+		g.fds += st.counts.FDs
+		for _, port := range p.Ports {
+			g.ports[port] = true
+		}
+	}
+
+	// 单进程指标只保留最活跃的 maxProcs 个
+	top := stats
+	dropped := 0
+	if e.maxProcs < 0 {
+		top, dropped = nil, len(stats)
+	} else if len(stats) > e.maxProcs {
+		top = append([]procStat(nil), stats...)
+		sort.SliceStable(top, func(i, j int) bool {
+			if top[i].CpuPercent != top[j].CpuPercent {
+				return top[i].CpuPercent > top[j].CpuPercent
+			}
+			return top[i].MemoryUsage > top[j].MemoryUsage
+		})
+		top, dropped = top[:e.maxProcs], len(stats)-e.maxProcs
+	}
+	sort.Slice(top, func(i, j int) bool { return top[i].PID < top[j].PID })
+
+	var names []string
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w := &writer{}
+	procLabels := func(p procStat) []string {
+		return []string{"pid", strconv.Itoa(int(p.PID)), "name", p.Name, "user", p.User, "group", p.group}
+	}
+
+	w.family("quell_process_cpu_percent", "CPU usage of the process in percent of one core.", "gauge")
+	for _, p := range top {
+		w.sample("quell_process_cpu_percent", procLabels(p), p.CpuPercent)
+	}
+	w.family("quell_process_cpu_seconds_total", "Total user and system CPU time of the process.", "counter")
+	for _, p := range top {
+		w.sample("quell_process_cpu_seconds_total", procLabels(p), p.CPUTime)
+	}
+	w.family("quell_process_resident_memory_bytes", "Resident set size of the process.", "gauge")
+	for _, p := range top {
+		w.sample("quell_process_resident_memory_bytes", procLabels(p), float64(p.MemoryUsage))
+	}
+	w.family("quell_process_threads", "Number of threads of the process.", "gauge")
+	for _, p := range top {
+		w.sample("quell_process_threads", procLabels(p), float64(p.counts.Threads))
+	}
+	w.family("quell_process_open_fds", "Number of open file descriptors (only when readable).", "gauge")
+	for _, p := range top {
+		if p.counts.HasFDs {
+			w.sample("quell_process_open_fds", procLabels(p), float64(p.counts.FDs))
+		}
+	}
+	w.family("quell_process_listen_port", "TCP port the process is listening on (value is always 1).", "gauge")
+	for _, p := range top {
+		for _, port := range p.Ports {
+			w.sample("quell_process_listen_port", append(procLabels(p), "port", strconv.Itoa(port)), 1)
+		}
+	}
+
+	w.family("quell_group_up", "1 if at least one process of the group is running.", "gauge")
+	for _, name := range names {
+		up := 0.0
+		if groups[name].procs > 0 {
+			up = 1
+		}
+		w.sample("quell_group_up", []string{"group", name}, up)
+	}
+	groupFamilies := []struct {
+		name, help, typ string
+		value           func(g *groupStat) float64
+	}{
+		{"quell_group_processes", "Number of processes in the group.", "gauge", func(g *groupStat) float64 { return float64(g.procs) }},
+		{"quell_group_cpu_percent", "Summed CPU usage of the group in percent of one core.", "gauge", func(g *groupStat) float64 { return g.cpu }},
+		{"quell_group_cpu_seconds", "Summed CPU time of the running processes in the group (drops when processes exit).", "gauge", func(g *groupStat) float64 { return g.cpuSecs }},
+		{"quell_group_resident_memory_bytes", "Summed resident set size of the group.", "gauge", func(g *groupStat) float64 { return float64(g.rss) }},
+		{"quell_group_threads", "Summed thread count of the group.", "gauge", func(g *groupStat) float64 { return float64(g.threads) }},
+		{"quell_group_open_fds", "Summed open file descriptors of the group (readable processes only).", "gauge", func(g *groupStat) float64 { return float64(g.fds) }},
+	}
+	for _, f := range groupFamilies {
+		w.family(f.name, f.help, f.typ)
+		for _, name := range names {
+			w.sample(f.name, []string{"group", name}, f.value(groups[name]))
+		}
+	}
+	w.family("quell_group_listen_port", "TCP port some process of the group is listening on (value is always 1).", "gauge")
+	for _, name := range names {
+		var ports []int
+		for port := range groups[name].ports {
+			ports = append(ports, port)
+		}
+		sort.Ints(ports)
+		for _, port := range ports {
+			w.sample("quell_group_listen_port", []string{"group", name, "port", strconv.Itoa(port)}, 1)
+		}
+	}
+
+	w.family("quell_processes", "Number of processes seen in the last scan.", "gauge")
+	w.sample("quell_processes", nil, float64(len(procs)))
+	w.family("quell_exporter_processes_dropped", "Processes left out of per-process metrics by the cardinality cap.", "gauge")
+	w.sample("quell_exporter_processes_dropped", nil, float64(dropped))
+	w.family("quell_exporter_scrape_duration_seconds", "Time spent scanning processes for this scrape.", "gauge")
+	w.sample("quell_exporter_scrape_duration_seconds", nil, time.Since(start).Seconds())
+
+	return w.buf.Bytes()
+}
+
+// writer 手写 Prometheus 文本格式，避免引入客户端库
+type writer struct {
+	buf bytes.Buffer
+}
+
+func (w *writer) family(name, help, typ string) {
+	fmt.Fprintf(&w.buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// sample labels 为 key, value 交替排列
+func (w *writer) sample(name string, labels []string, value float64) {
+	w.buf.WriteString(name)
+	if len(labels) > 0 {
+		w.buf.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				w.buf.WriteByte(',')
+			}
+			fmt.Fprintf(&w.buf, `%s="%s"`, labels[i], labelEscaper.Replace(labels[i+1]))
+		}
+		w.buf.WriteByte('}')
+	}
+	w.buf.WriteByte(' ')
+	w.buf.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	w.buf.WriteByte('\n')
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Microindole/quell/internal/config"
+	"github.com/Microindole/quell/internal/core"
+	"github.com/Microindole/quell/internal/snapshot"
+)
+
+// testProcesses 两个 nginx、一个按命令行归组的 gunicorn，以及两个不属于任何分组的进程
+var testProcesses = []core.Process{
+	{PID: 10, Name: "nginx", User: "www", CpuPercent: 5, MemoryUsage: 100, Ports: []int{80, 443}},
+	{PID: 11, Name: "nginx", User: "www", CpuPercent: 1, MemoryUsage: 50, Ports: []int{80}},
+	{PID: 20, Name: "python3", User: "app", Cmdline: "python3 -m gunicorn app:wsgi", CpuPercent: 30, MemoryUsage: 400},
+	{PID: 30, Name: "python3", User: "app", Cmdline: "python3 cron.py", CpuPercent: 0.5, MemoryUsage: 10},
+	{PID: 40, Name: "bash", User: "root", CpuPercent: 0, MemoryUsage: 5},
+}
+
+// scrape 通过本地 HTTP 服务抓取一次指标
+func scrape(t *testing.T, cfg config.MetricsConfig) string {
+	t.Helper()
+	frames := []snapshot.Frame{{Time: time.Unix(1700000000, 0), Processes: testProcesses}}
+	e, err := NewExporter(core.NewService(snapshot.NewReplayProvider(frames)), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(e)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestExporterGroups(t *testing.T) {
+	body := scrape(t, config.MetricsConfig{Groups: []config.GroupRule{
+		{Name: "web", Match: "^nginx$"},
+		{Name: "api", Match: "^python", Cmdline: "gunicorn"},
+		{Name: "db", Match: "^postgres$"},
+	}})
+
+	for _, want := range []string{
+		`quell_group_processes{group="web"} 2`,
+		`quell_group_cpu_percent{group="web"} 6`,
+		`quell_group_resident_memory_bytes{group="web"} 150`,
+		`quell_group_listen_port{group="web",port="80"} 1`,
+		`quell_group_listen_port{group="web",port="443"} 1`,
+		// 名字与命令行需同时命中
+		`quell_group_processes{group="api"} 1`,
+		`quell_group_processes{group="other"} 2`,
+		// 配置了但没有进程的分组仍然导出，便于告警
+		`quell_group_up{group="db"} 0`,
+		`quell_group_up{group="web"} 1`,
+		`quell_process_cpu_percent{pid="20",name="python3",user="app",group="api"} 30`,
+		`quell_processes 5`,
+		`quell_exporter_processes_dropped 0`,
+	} {
+		if !strings.Contains(body, want+"\n") {
+			t.Errorf("missing %s", want)
+		}
+	}
+	// 同一端口只导出一次
+	if n := strings.Count(body, `quell_group_listen_port{group="web",port="80"}`); n != 1 {
+		t.Errorf("web port 80 exported %d times", n)
+	}
+}
+
+func TestExporterCardinalityCap(t *testing.T) {
+	tests := []struct {
+		name        string
+		max         int
+		wantPIDs    []string
+		wantDropped string
+	}{
+		{"default keeps all", 0, []string{"10", "11", "20", "30", "40"}, "0"},
+		{"keeps busiest", 2, []string{"10", "20"}, "3"},
+		{"disabled", -1, nil, "5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := scrape(t, config.MetricsConfig{MaxProcesses: tt.max})
+
+			var pids []string
+			for _, line := range strings.Split(body, "\n") {
+				if rest, ok := strings.CutPrefix(line, `quell_process_cpu_percent{pid="`); ok {
+					pids = append(pids, rest[:strings.IndexByte(rest, '"')])
+				}
+			}
+			if strings.Join(pids, ",") != strings.Join(tt.wantPIDs, ",") {
+				t.Errorf("per-process series for PIDs %v, want %v", pids, tt.wantPIDs)
+			}
+			if want := "quell_exporter_processes_dropped " + tt.wantDropped + "\n"; !strings.Contains(body, want) {
+				t.Errorf("missing %q", strings.TrimSpace(want))
+			}
+			// 分组指标不受上限影响
+			if !strings.Contains(body, `quell_group_processes{group="other"} 5`+"\n") {
+				t.Error("group metrics should cover every process")
+			}
+		})
+	}
+}
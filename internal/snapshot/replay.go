@@ -0,0 +1,226 @@
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Microindole/quell/internal/core"
+)
+
+// ErrReplay 回放期间禁止任何会改变系统状态的操作
+var ErrReplay = errors.New("actions are disabled during replay")
+
+const (
+	minSpeed = 0.25
+	maxSpeed = 64
+)
+
+// ReplayProvider 把录制文件当作数据源，实现 core.Provider
+// 回放位置随真实时间按 speed 倍速前进，可暂停和跳转
+type ReplayProvider struct {
+	mu     sync.Mutex
+	frames []Frame
+	pos    time.Duration // anchor 时刻相对第一帧的偏移
+	anchor time.Time
+	speed  float64
+	paused bool
+	clock  func() time.Time
+}
+
+func NewReplayProvider(frames []Frame) *ReplayProvider {
+	return &ReplayProvider{frames: frames, speed: 1, clock: time.Now, anchor: time.Now()}
+}
+
+// SetClock 替换时钟 (默认 time.Now)；测试中传入固定时钟可以让回放完全确定
+// 回放位置停在上一次固定下来的位置 (新建时为开头)，不会混入旧时钟走过的时间
+func (r *ReplayProvider) SetClock(clock func() time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clock = clock
+	r.anchor = clock()
+}
+
+// position 当前回放位置 (相对第一帧)，调用方需持有锁
+func (r *ReplayProvider) position() time.Duration {
+	pos := r.pos
+	if !r.paused {
+		pos += time.Duration(float64(r.clock().Sub(r.anchor)) * r.speed)
+	}
+	if length := r.length(); pos > length {
+		pos = length
+	}
+	return pos
+}
+
+// rebase 把当前位置固定下来，之后修改速度或暂停状态不会让位置跳变
+func (r *ReplayProvider) rebase() {
+	r.pos = r.position()
+	r.anchor = r.clock()
+}
+
+func (r *ReplayProvider) length() time.Duration {
+	return r.frames[len(r.frames)-1].Time.Sub(r.frames[0].Time)
+}
+
+// frame 当前位置对应的帧：时间不晚于当前位置的最后一帧
+func (r *ReplayProvider) frame() Frame {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	at := r.frames[0].Time.Add(r.position())
+	i := sort.Search(len(r.frames), func(i int) bool { return r.frames[i].Time.After(at) })
+	if i > 0 {
+		i--
+	}
+	return r.frames[i]
+}
+
+// Now 回放位置对应的录制时间
+func (r *ReplayProvider) Now() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.frames[0].Time.Add(r.position())
+}
+
+// TogglePause 播放 / 暂停；已经播放到末尾时从头开始
+func (r *ReplayProvider) TogglePause() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rebase()
+	if r.pos >= r.length() {
+		r.pos = 0
+		r.paused = false
+		return
+	}
+	r.paused = !r.paused
+}
+
+// Seek 前后跳转 d
+func (r *ReplayProvider) Seek(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rebase()
+	r.pos += d
+	if r.pos < 0 {
+		r.pos = 0
+	}
+	if length := r.length(); r.pos > length {
+		r.pos = length
+	}
+}
+
+// ScaleSpeed 把播放速度乘以 factor，限制在 0.25x - 64x
+func (r *ReplayProvider) ScaleSpeed(factor float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rebase()
+	r.speed *= factor
+	if r.speed < minSpeed {
+		r.speed = minSpeed
+	}
+	if r.speed > maxSpeed {
+		r.speed = maxSpeed
+	}
+}
+
+// Status 状态栏显示的回放进度
+func (r *ReplayProvider) Status() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pos, length := r.position(), r.length()
+	state := "▶"
+	if r.paused || pos >= length {
+		state = "⏸"
+	}
+	return fmt.Sprintf("%s REPLAY %s (%s / %s) %gx", state,
+		r.frames[0].Time.Add(pos).Format("2006-01-02 15:04:05"),
+		pos.Truncate(time.Second), length.Truncate(time.Second), r.speed)
+}
+
+// ListProcesses 返回当前帧的进程列表 (拷贝，调用方可以随意修改)
+func (r *ReplayProvider) ListProcesses() ([]core.Process, error) {
+	fr := r.frame()
+	procs := make([]core.Process, len(fr.Processes))
+	copy(procs, fr.Processes)
+	return procs, nil
+}
+
+func (r *ReplayProvider) GetSystemStats() (core.SystemStats, error) {
+	return r.frame().Stats, nil
+}
+
+func (r *ReplayProvider) GetConnections(pid int32) ([]core.Connection, error) {
+	return r.frame().Connections[pid], nil
+}
+
+func (r *ReplayProvider) GetCreateTime(pid int32) (int64, error) {
+	for _, p := range r.frame().Processes {
+		if p.PID == pid {
+			return p.CreateTime, nil
+		}
+	}
+	return 0, fmt.Errorf("process %d not found in recording", pid)
+}
+
+func (r *ReplayProvider) Kill(pid int32, force bool) error           { return ErrReplay }
+func (r *ReplayProvider) Suspend(pid int32) error                    { return ErrReplay }
+func (r *ReplayProvider) Resume(pid int32) error                     { return ErrReplay }
+func (r *ReplayProvider) Signal(pid int32, sig syscall.Signal) error { return ErrReplay }
+func (r *ReplayProvider) SetNice(pid int32, nice int) error          { return ErrReplay }
+func (r *ReplayProvider) SetIOPriority(pid int32, prio core.IOPriority) error {
+	return ErrReplay
+}
+
+func (r *ReplayProvider) SetAffinity(pid int32, cpus core.CPUSet, threads bool) error {
+	return ErrReplay
+}
+
+// GetAffinity 取自录制时扫描到的值；cgroup cpuset 与在线 CPU 没有录制
+func (r *ReplayProvider) GetAffinity(pid int32) (core.CPUSet, error) {
+	for _, p := range r.frame().Processes {
+		if p.PID == pid && p.Affinity != nil {
+			return p.Affinity, nil
+		}
+	}
+	return nil, core.ErrNotSupported
+}
+func (r *ReplayProvider) GetCpuset(pid int32) (core.CPUSet, error) { return nil, core.ErrNotSupported }
+func (r *ReplayProvider) OnlineCPUs() (core.CPUSet, error)         { return nil, core.ErrNotSupported }
+
+// GetIOPriority 取自录制时扫描到的值
+func (r *ReplayProvider) GetIOPriority(pid int32) (core.IOPriority, error) {
+	for _, p := range r.frame().Processes {
+		if p.PID == pid && p.HasPriority {
+			return p.IOPriority, nil
+		}
+	}
+	return core.IOPriority{}, core.ErrNotSupported
+}
+
+// 录制文件只包含进程列表与连接，深度检视的数据不可用
+func (r *ReplayProvider) GetEnviron(pid int32) ([]string, error) { return nil, core.ErrNotSupported }
+func (r *ReplayProvider) GetOpenFiles(pid int32) ([]core.OpenFile, error) {
+	return nil, core.ErrNotSupported
+}
+func (r *ReplayProvider) GetLimits(pid int32) ([]core.Limit, error) { return nil, core.ErrNotSupported }
+func (r *ReplayProvider) GetThreads(pid int32) ([]core.Thread, error) {
+	return nil, core.ErrNotSupported
+}
+func (r *ReplayProvider) GetFileRefs(pid int32) ([]core.FileRef, error) {
+	return nil, core.ErrNotSupported
+}
+func (r *ReplayProvider) GetMemoryMaps(pid int32) ([]core.MemoryMap, error) {
+	return nil, core.ErrNotSupported
+}
+func (r *ReplayProvider) GetMemoryDetail(pid int32) (core.MemoryDetail, error) {
+	return core.MemoryDetail{}, core.ErrNotSupported
+}
+func (r *ReplayProvider) GetResourceCounts(pid int32) (core.ResourceCounts, error) {
+	return core.ResourceCounts{}, core.ErrNotSupported
+}
+func (r *ReplayProvider) GetSessionInfo(pid int32) (core.SessionInfo, error) {
+	return core.SessionInfo{}, core.ErrNotSupported
+}
@@ -0,0 +1,77 @@
+package snapshot
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Microindole/quell/internal/core"
+)
+
+// fakeClock 手动推进的时钟
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func testFrames(start time.Time) []Frame {
+	frame := func(offset time.Duration, cpu float64) Frame {
+		return Frame{
+			Time:      start.Add(offset),
+			Processes: []core.Process{{PID: 42, Name: "worker", CreateTime: 1000, CpuPercent: cpu}},
+		}
+	}
+	return []Frame{frame(0, 10), frame(2*time.Second, 20), frame(4*time.Second, 30)}
+}
+
+func TestReplayIsDeterministic(t *testing.T) {
+	start := time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}
+	r := NewReplayProvider(testFrames(start))
+	r.SetClock(clock.Now)
+	svc := core.NewService(r)
+
+	steps := []struct {
+		name    string
+		act     func()
+		wantAt  time.Duration // 相对第一帧
+		wantCPU float64
+	}{
+		{"start", func() {}, 0, 10},
+		{"before second frame", func() { clock.Advance(1500 * time.Millisecond) }, 1500 * time.Millisecond, 10},
+		{"second frame", func() { clock.Advance(time.Second) }, 2500 * time.Millisecond, 20},
+		{"paused", func() { r.TogglePause(); clock.Advance(time.Minute) }, 2500 * time.Millisecond, 20},
+		{"seek back clamps", func() { r.Seek(-time.Hour) }, 0, 10},
+		{"resume at 2x", func() { r.TogglePause(); r.ScaleSpeed(2); clock.Advance(time.Second) }, 2 * time.Second, 20},
+		{"end clamps", func() { clock.Advance(time.Hour) }, 4 * time.Second, 30},
+	}
+	for _, step := range steps {
+		step.act()
+		want := start.Add(step.wantAt)
+		if got := r.Now(); !got.Equal(want) {
+			t.Fatalf("%s: Now() = %v, want %v", step.name, got, want)
+		}
+		// 图表与采样使用 Service.Now，回放时必须是录制时间而不是墙上时间
+		if got := svc.Now(); !got.Equal(want) {
+			t.Fatalf("%s: Service.Now() = %v, want %v", step.name, got, want)
+		}
+		procs, err := svc.GetProcesses()
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		// 录制时已经算好的 CPU 占用率原样返回，不按差值重新计算
+		if len(procs) != 1 || procs[0].CpuPercent != step.wantCPU {
+			t.Fatalf("%s: processes = %+v, want CPU %v", step.name, procs, step.wantCPU)
+		}
+	}
+}
+
+func TestReplayRefusesActions(t *testing.T) {
+	r := NewReplayProvider(testFrames(time.Unix(0, 0)))
+	if err := r.Kill(42, true); !errors.Is(err, ErrReplay) {
+		t.Fatalf("Kill = %v, want ErrReplay", err)
+	}
+	if err := r.SetNice(42, 5); !errors.Is(err, ErrReplay) {
+		t.Fatalf("SetNice = %v, want ErrReplay", err)
+	}
+}
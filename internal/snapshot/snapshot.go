@@ -0,0 +1,116 @@
+package snapshot
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Microindole/quell/internal/core"
+)
+
+// Frame 某一时刻的完整快照
+// 进程的 CpuPercent 与 I/O 速率在录制时已经算好，回放时不再重新计算
+type Frame struct {
+	Time        time.Time                   `json:"time"`
+	Processes   []core.Process              `json:"processes"`
+	Connections map[int32][]core.Connection `json:"connections,omitempty"` // 只录制有监听端口的进程
+	Stats       core.SystemStats            `json:"stats"`
+}
+
+// ErrEmpty 录制文件中没有任何帧
+var ErrEmpty = errors.New("recording contains no frames")
+
+// Writer 把帧以 gzip 压缩的 JSON Lines 格式写入文件
+type Writer struct {
+	f   *os.File
+	gz  *gzip.Writer
+	enc *json.Encoder
+}
+
+func Create(path string) (*Writer, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	gz := gzip.NewWriter(f)
+	return &Writer{f: f, gz: gz, enc: json.NewEncoder(gz)}, nil
+}
+
+// Write 追加一帧，并立即刷新压缩流，录制被强行中断时已写入的帧仍然可读
+func (w *Writer) Write(fr Frame) error {
+	if err := w.enc.Encode(fr); err != nil {
+		return err
+	}
+	return w.gz.Flush()
+}
+
+func (w *Writer) Close() error {
+	if err := w.gz.Close(); err != nil {
+		_ = w.f.Close()
+		return err
+	}
+	return w.f.Close()
+}
+
+// Load 读取录制文件中的全部帧
+// 文件末尾不完整 (录制进程被杀) 时返回已经读到的帧
+func Load(path string) ([]Frame, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	gz, err := gzip.NewReader(bufio.NewReader(f))
+	if err != nil {
+		return nil, fmt.Errorf("%s: not a quell recording: %w", path, err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	var frames []Frame
+	dec := json.NewDecoder(gz)
+	for {
+		var fr Frame
+		err := dec.Decode(&fr)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if len(frames) > 0 && (errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, gzip.ErrChecksum)) {
+				break
+			}
+			return nil, fmt.Errorf("%s: frame %d: %w", path, len(frames)+1, err)
+		}
+		frames = append(frames, fr)
+	}
+	if len(frames) == 0 {
+		return nil, ErrEmpty
+	}
+	return frames, nil
+}
+
+// Capture 通过 Service 采集一帧
+func Capture(svc *core.Service) (Frame, error) {
+	procs, err := svc.GetProcesses()
+	if err != nil {
+		return Frame{}, err
+	}
+	fr := Frame{Time: time.Now(), Processes: procs, Connections: make(map[int32][]core.Connection)}
+	if stats, err := svc.GetSystemStats(); err == nil {
+		fr.Stats = stats
+	}
+	for _, p := range procs {
+		if len(p.Ports) == 0 {
+			continue
+		}
+		if conns, err := svc.GetConnections(p.PID); err == nil && len(conns) > 0 {
+			fr.Connections[p.PID] = conns
+		}
+	}
+	return fr, nil
+}
@@ -0,0 +1,100 @@
+//go:build linux
+
+package system
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Microindole/quell/internal/core"
+	"golang.org/x/sys/unix"
+)
+
+const cgroupRoot = "/sys/fs/cgroup"
+
+func getAffinity(pid int32) (core.CPUSet, error) {
+	var set unix.CPUSet
+	if err := unix.SchedGetaffinity(int(pid), &set); err != nil {
+		return nil, err
+	}
+	n := set.Count()
+	cpus := make(core.CPUSet, 0, n)
+	for cpu := 0; len(cpus) < n; cpu++ {
+		if set.IsSet(cpu) {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus, nil
+}
+
+// setAffinity sched_setaffinity 只作用于一个线程，threads 为 true 时逐个设置所有线程
+func setAffinity(pid int32, cpus core.CPUSet, threads bool) error {
+	var set unix.CPUSet
+	for _, cpu := range cpus {
+		// unix.CPUSet.Set 会悄悄忽略超出范围的编号
+		if cpu < 0 || cpu >= core.MaxCPUs {
+			return fmt.Errorf("CPU %d out of range (max %d)", cpu, core.MaxCPUs-1)
+		}
+		set.Set(cpu)
+	}
+	if !threads {
+		return unix.SchedSetaffinity(int(pid), &set)
+	}
+	return eachTask(pid, func(tid int) error { return unix.SchedSetaffinity(tid, &set) })
+}
+
+// readCpuset 进程所在 cgroup 实际可用的 CPU
+// cgroup v1 读 cpuset 层级；v2 的子 cgroup 没有启用 cpuset 控制器时没有这个文件，逐级向上查找
+func readCpuset(pid int32) (core.CPUSet, error) {
+	data, err := os.ReadFile(procPath(pid, "cgroup"))
+	if err != nil {
+		return nil, wrapProcErr(err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		var root string
+		var files []string
+		switch {
+		case hasController(parts[1], "cpuset"):
+			root, files = filepath.Join(cgroupRoot, "cpuset"), []string{"cpuset.effective_cpus", "cpuset.cpus"}
+		case parts[0] == "0" && parts[1] == "":
+			root, files = cgroupRoot, []string{"cpuset.cpus.effective"}
+		default:
+			continue
+		}
+		for dir := filepath.Join(root, parts[2]); strings.HasPrefix(dir, root); dir = filepath.Dir(dir) {
+			for _, f := range files {
+				if b, err := os.ReadFile(filepath.Join(dir, f)); err == nil && len(bytes.TrimSpace(b)) > 0 {
+					return core.ParseCPUSet(string(b))
+				}
+			}
+			if dir == root {
+				break
+			}
+		}
+	}
+	return nil, core.ErrNotSupported
+}
+
+func hasController(list, name string) bool {
+	for _, c := range strings.Split(list, ",") {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+func onlineCPUs() (core.CPUSet, error) {
+	data, err := os.ReadFile("/sys/devices/system/cpu/online")
+	if err != nil {
+		return nil, err
+	}
+	return core.ParseCPUSet(string(data))
+}
@@ -0,0 +1,23 @@
+//go:build !linux
+
+package system
+
+import "github.com/Microindole/quell/internal/core"
+
+// macOS 与 Windows 暂不支持 CPU 亲和性
+
+func getAffinity(pid int32) (core.CPUSet, error) {
+	return nil, core.ErrNotSupported
+}
+
+func setAffinity(pid int32, cpus core.CPUSet, threads bool) error {
+	return core.ErrNotSupported
+}
+
+func readCpuset(pid int32) (core.CPUSet, error) {
+	return nil, core.ErrNotSupported
+}
+
+func onlineCPUs() (core.CPUSet, error) {
+	return nil, core.ErrNotSupported
+}
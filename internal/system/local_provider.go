@@ -4,6 +4,7 @@ import (
 	"path/filepath"
 	"sort"
 	"sync"
+	"syscall"
 
 	"github.com/Microindole/quell/internal/core"
 	"github.com/shirou/gopsutil/v3/net"
@@ -100,7 +101,11 @@ func (l *LocalProvider) ListProcesses() ([]core.Process, error) {
 		ppid, _ := proc.Ppid()
 
 		// 获取动态数据
-		cpuPercent, _ := proc.Percent(0)
+		// 只取累计 CPU 时间，百分比由 core 层按两次扫描的差值计算
+		var cpuTime float64
+		if times, err := proc.Times(); err == nil {
+			cpuTime = times.User + times.System
+		}
 		memInfo, _ := proc.MemoryInfo()
 		var memUsage uint64
 		if memInfo != nil {
@@ -111,7 +116,7 @@ func (l *LocalProvider) ListProcesses() ([]core.Process, error) {
 
 		statusStr := GetProcessStatus(proc)
 
-		results = append(results, core.Process{
+		item := core.Process{
 			PID:         pid,
 			PPID:        ppid,
 			Name:        l.refineName(proc, name),
@@ -119,11 +124,32 @@ func (l *LocalProvider) ListProcesses() ([]core.Process, error) {
 			Protocol:    "TCP",
 			Cmdline:     l.getCmdlineSafe(proc),
 			MemoryUsage: memUsage,
-			CpuPercent:  cpuPercent,
+			CPUTime:     cpuTime,
 			User:        user,
 			Status:      statusStr,
 			CreateTime:  currentCreateTime,
-		})
+		}
+
+		// /proc/<pid>/io 需要与目标进程同属一个用户 (或 root)，读不到时保持 HasIO = false
+		if io, err := proc.IOCounters(); err == nil && io != nil {
+			item.HasIO = true
+			item.IOReadBytes = io.ReadBytes
+			item.IOWriteBytes = io.WriteBytes
+			item.IOReadOps = io.ReadCount
+			item.IOWriteOps = io.WriteCount
+		}
+
+		if nice, prio, io, err := readPriority(pid); err == nil {
+			item.HasPriority = true
+			item.Nice = nice
+			item.Priority = prio
+			item.IOPriority = io
+		}
+		if cpus, err := getAffinity(pid); err == nil {
+			item.Affinity = cpus
+		}
+
+		results = append(results, item)
 	}
 
 	// 清理缓存
@@ -233,3 +259,76 @@ func (l *LocalProvider) GetConnections(pid int32) ([]core.Connection, error) {
 	}
 	return results, nil
 }
+
+func (l *LocalProvider) GetEnviron(pid int32) ([]string, error) {
+	return readEnviron(pid)
+}
+
+func (l *LocalProvider) GetOpenFiles(pid int32) ([]core.OpenFile, error) {
+	return readOpenFiles(pid)
+}
+
+func (l *LocalProvider) GetLimits(pid int32) ([]core.Limit, error) {
+	return readLimits(pid)
+}
+
+func (l *LocalProvider) GetThreads(pid int32) ([]core.Thread, error) {
+	return readThreads(pid)
+}
+
+// Signal 发送任意信号
+func (l *LocalProvider) Signal(pid int32, sig syscall.Signal) error {
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.SendSignal(sig)
+}
+
+func (l *LocalProvider) GetFileRefs(pid int32) ([]core.FileRef, error) {
+	return readFileRefs(pid)
+}
+
+func (l *LocalProvider) GetMemoryMaps(pid int32) ([]core.MemoryMap, error) {
+	return readMemoryMaps(pid)
+}
+
+func (l *LocalProvider) GetMemoryDetail(pid int32) (core.MemoryDetail, error) {
+	return readMemoryDetail(pid)
+}
+
+func (l *LocalProvider) GetResourceCounts(pid int32) (core.ResourceCounts, error) {
+	return readResourceCounts(pid)
+}
+
+func (l *LocalProvider) GetSessionInfo(pid int32) (core.SessionInfo, error) {
+	return readSessionInfo(pid)
+}
+
+func (l *LocalProvider) SetNice(pid int32, nice int) error {
+	return setNice(pid, nice)
+}
+
+func (l *LocalProvider) GetIOPriority(pid int32) (core.IOPriority, error) {
+	return getIOPriority(pid)
+}
+
+func (l *LocalProvider) SetIOPriority(pid int32, prio core.IOPriority) error {
+	return setIOPriority(pid, prio)
+}
+
+func (l *LocalProvider) GetAffinity(pid int32) (core.CPUSet, error) {
+	return getAffinity(pid)
+}
+
+func (l *LocalProvider) SetAffinity(pid int32, cpus core.CPUSet, threads bool) error {
+	return setAffinity(pid, cpus, threads)
+}
+
+func (l *LocalProvider) GetCpuset(pid int32) (core.CPUSet, error) {
+	return readCpuset(pid)
+}
+
+func (l *LocalProvider) OnlineCPUs() (core.CPUSet, error) {
+	return onlineCPUs()
+}
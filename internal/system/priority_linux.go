@@ -0,0 +1,100 @@
+//go:build linux
+
+package system
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"syscall"
+
+	"github.com/Microindole/quell/internal/core"
+)
+
+// ioprio_get / ioprio_set 的参数，见 man ioprio_set
+const (
+	ioprioWhoProcess = 1
+	ioprioClassShift = 13
+	ioprioLevelMask  = 1<<ioprioClassShift - 1
+)
+
+// readPriority 取自 stat 的 priority、nice 字段，I/O 优先级来自 ioprio_get
+func readPriority(pid int32) (nice, prio int32, io core.IOPriority, err error) {
+	data, err := os.ReadFile(procPath(pid, "stat"))
+	if err != nil {
+		return 0, 0, io, wrapProcErr(err)
+	}
+	_, fields, ok := parseStat(string(data))
+	if !ok || len(fields) < 17 {
+		return 0, 0, io, fmt.Errorf("malformed stat for pid %d", pid)
+	}
+	p, _ := strconv.ParseInt(fields[15], 10, 32)
+	n, _ := strconv.ParseInt(fields[16], 10, 32)
+	// 读不到 I/O 优先级时按未设置处理
+	io, _ = getIOPriority(pid)
+	return int32(n), int32(p), io, nil
+}
+
+// taskIDs 进程的所有线程；Linux 的 setpriority / ioprio_set 只作用于单个线程，
+// 所以要逐个设置，否则多线程程序只有主线程被降级
+func taskIDs(pid int32) []int {
+	entries, err := os.ReadDir(procPath(pid, "task"))
+	if err != nil {
+		return []int{int(pid)}
+	}
+	tids := make([]int, 0, len(entries))
+	for _, e := range entries {
+		if tid, err := strconv.Atoi(e.Name()); err == nil {
+			tids = append(tids, tid)
+		}
+	}
+	if len(tids) == 0 {
+		return []int{int(pid)}
+	}
+	return tids
+}
+
+// eachTask 对所有线程执行 set：主线程的错误原样返回，其余线程中途退出 (ESRCH) 的忽略
+func eachTask(pid int32, set func(tid int) error) error {
+	if err := set(int(pid)); err != nil {
+		return err
+	}
+	for _, tid := range taskIDs(pid) {
+		if tid == int(pid) {
+			continue
+		}
+		if err := set(tid); err != nil && !errors.Is(err, syscall.ESRCH) {
+			return err
+		}
+	}
+	return nil
+}
+
+func setNice(pid int32, nice int) error {
+	return eachTask(pid, func(tid int) error {
+		return syscall.Setpriority(syscall.PRIO_PROCESS, tid, nice)
+	})
+}
+
+func getIOPriority(pid int32) (core.IOPriority, error) {
+	r, _, errno := syscall.Syscall(syscall.SYS_IOPRIO_GET, ioprioWhoProcess, uintptr(pid), 0)
+	if errno != 0 {
+		return core.IOPriority{}, errno
+	}
+	return core.IOPriority{
+		Class: core.IOClass(r >> ioprioClassShift),
+		Level: int(r & ioprioLevelMask),
+	}, nil
+}
+
+func setIOPriority(pid int32, prio core.IOPriority) error {
+	value := uintptr(prio.Class)<<ioprioClassShift | uintptr(prio.Level)
+	return eachTask(pid, func(tid int) error {
+		_, _, errno := syscall.Syscall(syscall.SYS_IOPRIO_SET, ioprioWhoProcess, uintptr(tid), value)
+		if errno != 0 {
+			return errno
+		}
+		return nil
+	})
+}
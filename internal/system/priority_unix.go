@@ -0,0 +1,30 @@
+//go:build !linux && !windows
+
+package system
+
+import (
+	"syscall"
+
+	"github.com/Microindole/quell/internal/core"
+)
+
+// readPriority BSD 的 getpriority 直接返回 nice 值；没有 I/O 调度类别
+func readPriority(pid int32) (nice, prio int32, io core.IOPriority, err error) {
+	n, err := syscall.Getpriority(syscall.PRIO_PROCESS, int(pid))
+	if err != nil {
+		return 0, 0, io, err
+	}
+	return int32(n), int32(20 + n), io, nil
+}
+
+func setNice(pid int32, nice int) error {
+	return syscall.Setpriority(syscall.PRIO_PROCESS, int(pid), nice)
+}
+
+func getIOPriority(pid int32) (core.IOPriority, error) {
+	return core.IOPriority{}, core.ErrNotSupported
+}
+
+func setIOPriority(pid int32, prio core.IOPriority) error {
+	return core.ErrNotSupported
+}
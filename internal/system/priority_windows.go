@@ -0,0 +1,23 @@
+//go:build windows
+
+package system
+
+import "github.com/Microindole/quell/internal/core"
+
+// Windows 使用优先级类别而不是 nice 值，暂不支持
+
+func readPriority(pid int32) (nice, prio int32, io core.IOPriority, err error) {
+	return 0, 0, io, core.ErrNotSupported
+}
+
+func setNice(pid int32, nice int) error {
+	return core.ErrNotSupported
+}
+
+func getIOPriority(pid int32) (core.IOPriority, error) {
+	return core.IOPriority{}, core.ErrNotSupported
+}
+
+func setIOPriority(pid int32, prio core.IOPriority) error {
+	return core.ErrNotSupported
+}
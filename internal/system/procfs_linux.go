@@ -0,0 +1,407 @@
+//go:build linux
+
+package system
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/Microindole/quell/internal/core"
+)
+
+// clockTicks 即 USER_HZ，几乎所有 Linux 发行版都是 100
+const clockTicks = 100
+
+func procPath(pid int32, parts ...string) string {
+	return filepath.Join(append([]string{"/proc", strconv.Itoa(int(pid))}, parts...)...)
+}
+
+// wrapProcErr 把权限错误统一转换为 core.ErrPermissionDenied，方便 UI 给出提示
+func wrapProcErr(err error) error {
+	if errors.Is(err, fs.ErrPermission) {
+		return core.ErrPermissionDenied
+	}
+	return err
+}
+
+func readEnviron(pid int32) ([]string, error) {
+	data, err := os.ReadFile(procPath(pid, "environ"))
+	if err != nil {
+		return nil, wrapProcErr(err)
+	}
+	var env []string
+	for _, kv := range bytes.Split(data, []byte{0}) {
+		if len(kv) > 0 {
+			env = append(env, string(kv))
+		}
+	}
+	sort.Strings(env)
+	return env, nil
+}
+
+func readOpenFiles(pid int32) ([]core.OpenFile, error) {
+	entries, err := os.ReadDir(procPath(pid, "fd"))
+	if err != nil {
+		return nil, wrapProcErr(err)
+	}
+
+	var files []core.OpenFile
+	for _, e := range entries {
+		fd, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		target, err := os.Readlink(procPath(pid, "fd", e.Name()))
+		if err != nil {
+			// fd 可能在遍历过程中被关闭
+			continue
+		}
+		f := core.OpenFile{
+			Fd:     fd,
+			Type:   fdType(target),
+			Path:   target,
+			Offset: readFdOffset(pid, e.Name()),
+		}
+		if f.Type == "file" {
+			// 被删除的文件 readlink 结果带有 " (deleted)" 后缀
+			if p, ok := strings.CutSuffix(target, " (deleted)"); ok {
+				f.Path = p
+				f.Deleted = true
+			}
+			// stat fd 链接本身会跟随到真实文件，已删除的文件也能拿到大小
+			if info, err := os.Stat(procPath(pid, "fd", e.Name())); err == nil {
+				f.Size = info.Size()
+				if st, ok := info.Sys().(*syscall.Stat_t); ok {
+					f.Device = uint64(st.Dev)
+					f.Inode = uint64(st.Ino)
+				}
+			}
+		}
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Fd < files[j].Fd })
+	return files, nil
+}
+
+// fdType 根据 readlink 的结果推断 fd 类型
+func fdType(target string) string {
+	switch {
+	case strings.HasPrefix(target, "socket:"):
+		return "socket"
+	case strings.HasPrefix(target, "pipe:"):
+		return "pipe"
+	case strings.HasPrefix(target, "anon_inode:"):
+		return "anon_inode"
+	case strings.HasPrefix(target, "/memfd:"):
+		// memfd_create 创建的匿名内存，链接总是带 "(deleted)"，并不占用磁盘
+		return "memfd"
+	case strings.HasPrefix(target, "/dev/shm/"):
+		// /dev/shm 是 tmpfs 上的普通文件，删除后同样占着内存
+		return "file"
+	case strings.HasPrefix(target, "/dev/"):
+		return "device"
+	default:
+		return "file"
+	}
+}
+
+// readFdOffset 从 fdinfo 中读取 pos 字段
+func readFdOffset(pid int32, fd string) int64 {
+	f, err := os.Open(procPath(pid, "fdinfo", fd))
+	if err != nil {
+		return 0
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if v, ok := strings.CutPrefix(scanner.Text(), "pos:"); ok {
+			pos, _ := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			return pos
+		}
+	}
+	return 0
+}
+
+// readLimits 解析 /proc/<pid>/limits
+// 文件是定宽表格，列位置以表头为准
+func readLimits(pid int32) ([]core.Limit, error) {
+	data, err := os.ReadFile(procPath(pid, "limits"))
+	if err != nil {
+		return nil, wrapProcErr(err)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	header := lines[0]
+	softCol := strings.Index(header, "Soft Limit")
+	hardCol := strings.Index(header, "Hard Limit")
+	unitCol := strings.Index(header, "Units")
+	if softCol < 0 || hardCol < 0 || unitCol < 0 {
+		return nil, errors.New("unexpected limits format")
+	}
+
+	column := func(line string, from, to int) string {
+		if from >= len(line) {
+			return ""
+		}
+		if to > len(line) || to < 0 {
+			to = len(line)
+		}
+		return strings.TrimSpace(line[from:to])
+	}
+
+	var limits []core.Limit
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		limits = append(limits, core.Limit{
+			Resource: column(line, 0, softCol),
+			Soft:     column(line, softCol, hardCol),
+			Hard:     column(line, hardCol, unitCol),
+			Unit:     column(line, unitCol, -1),
+		})
+	}
+	return limits, nil
+}
+
+func readThreads(pid int32) ([]core.Thread, error) {
+	entries, err := os.ReadDir(procPath(pid, "task"))
+	if err != nil {
+		return nil, wrapProcErr(err)
+	}
+
+	var threads []core.Thread
+	for _, e := range entries {
+		tid, err := strconv.ParseInt(e.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(procPath(pid, "task", e.Name(), "stat"))
+		if err != nil {
+			continue
+		}
+		name, fields, ok := parseStat(string(data))
+		if !ok || len(fields) < 13 {
+			continue
+		}
+		utime, _ := strconv.ParseFloat(fields[11], 64)
+		stime, _ := strconv.ParseFloat(fields[12], 64)
+		threads = append(threads, core.Thread{
+			TID:     int32(tid),
+			Name:    name,
+			State:   fields[0],
+			CPUTime: (utime + stime) / clockTicks,
+		})
+	}
+	sort.Slice(threads, func(i, j int) bool { return threads[i].TID < threads[j].TID })
+	return threads, nil
+}
+
+// readResourceCounts 线程数取自 stat 的 num_threads，fd 数为 fd 目录的条目数
+func readResourceCounts(pid int32) (core.ResourceCounts, error) {
+	data, err := os.ReadFile(procPath(pid, "stat"))
+	if err != nil {
+		return core.ResourceCounts{}, wrapProcErr(err)
+	}
+	_, fields, ok := parseStat(string(data))
+	if !ok || len(fields) < 18 {
+		return core.ResourceCounts{}, fmt.Errorf("malformed stat for pid %d", pid)
+	}
+	var rc core.ResourceCounts
+	rc.Threads, _ = strconv.Atoi(fields[17])
+
+	if entries, err := os.ReadDir(procPath(pid, "fd")); err == nil {
+		rc.FDs = len(entries)
+		rc.HasFDs = true
+	}
+	return rc, nil
+}
+
+// pfKthread 是 stat flags 中标记内核线程的位 (PF_KTHREAD)
+const pfKthread = 0x00200000
+
+// readSessionInfo 取自 stat 的 session、tty_nr 和 flags 字段
+func readSessionInfo(pid int32) (core.SessionInfo, error) {
+	data, err := os.ReadFile(procPath(pid, "stat"))
+	if err != nil {
+		return core.SessionInfo{}, wrapProcErr(err)
+	}
+	_, fields, ok := parseStat(string(data))
+	if !ok || len(fields) < 7 {
+		return core.SessionInfo{}, fmt.Errorf("malformed stat for pid %d", pid)
+	}
+	sid, _ := strconv.ParseInt(fields[3], 10, 32)
+	tty, _ := strconv.ParseInt(fields[4], 10, 32)
+	flags, _ := strconv.ParseUint(fields[6], 10, 64)
+	return core.SessionInfo{
+		SID:    int32(sid),
+		TTY:    int32(tty),
+		Kernel: flags&pfKthread != 0,
+	}, nil
+}
+
+// parseStat 拆分 stat 文件：comm 可能包含空格和括号，所以以最后一个 ')' 为界
+// 返回的 fields[0] 对应 man proc 中的第 3 个字段 (state)
+func parseStat(stat string) (string, []string, bool) {
+	open := strings.IndexByte(stat, '(')
+	end := strings.LastIndexByte(stat, ')')
+	if open < 0 || end < open {
+		return "", nil, false
+	}
+	return stat[open+1 : end], strings.Fields(stat[end+1:]), true
+}
+
+// readFileRefs 汇总进程对文件系统的所有引用
+// 部分条目无权限读取时返回能读到的部分；全部不可读时返回 ErrPermissionDenied
+func readFileRefs(pid int32) ([]core.FileRef, error) {
+	var refs []core.FileRef
+	denied := 0
+
+	for _, kind := range []string{core.RefCwd, core.RefRoot, core.RefExe} {
+		target, err := os.Readlink(procPath(pid, kind))
+		if err != nil {
+			if errors.Is(err, fs.ErrPermission) {
+				denied++
+			}
+			continue
+		}
+		refs = append(refs, core.FileRef{Kind: kind, Path: strings.TrimSuffix(target, " (deleted)")})
+	}
+
+	files, err := readOpenFiles(pid)
+	if err != nil {
+		denied++
+	}
+	for _, f := range files {
+		if strings.HasPrefix(f.Path, "/") {
+			refs = append(refs, core.FileRef{Kind: core.RefFd, Fd: f.Fd, Path: f.Path})
+		}
+	}
+
+	paths, err := readMappedPaths(pid)
+	if err != nil {
+		denied++
+	}
+	for _, p := range paths {
+		refs = append(refs, core.FileRef{Kind: core.RefMmap, Path: p})
+	}
+
+	if len(refs) == 0 && denied > 0 {
+		return nil, core.ErrPermissionDenied
+	}
+	return refs, nil
+}
+
+// readMappedPaths 返回映射的文件路径 (去重)
+func readMappedPaths(pid int32) ([]string, error) {
+	maps, err := readMemoryMaps(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var paths []string
+	for _, m := range maps {
+		if strings.HasPrefix(m.Path, "/") && !seen[m.Path] {
+			seen[m.Path] = true
+			paths = append(paths, m.Path)
+		}
+	}
+	return paths, nil
+}
+
+// readMemoryMaps 解析 /proc/<pid>/maps
+func readMemoryMaps(pid int32) ([]core.MemoryMap, error) {
+	f, err := os.Open(procPath(pid, "maps"))
+	if err != nil {
+		return nil, wrapProcErr(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var maps []core.MemoryMap
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// 格式: address perms offset dev inode [pathname]
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 {
+			continue
+		}
+		start, end, ok := strings.Cut(fields[0], "-")
+		if !ok {
+			continue
+		}
+		m := core.MemoryMap{Perms: fields[1]}
+		m.Start, _ = strconv.ParseUint(start, 16, 64)
+		m.End, _ = strconv.ParseUint(end, 16, 64)
+		m.Offset, _ = strconv.ParseUint(fields[2], 16, 64)
+		if len(fields) > 5 {
+			m.Path = strings.Join(fields[5:], " ")
+			if p, ok := strings.CutSuffix(m.Path, " (deleted)"); ok {
+				m.Path = p
+				m.Deleted = true
+			}
+		}
+		maps = append(maps, m)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, wrapProcErr(err)
+	}
+	return maps, nil
+}
+
+// readMemoryDetail 解析 /proc/<pid>/smaps_rollup (Linux 4.14+)
+func readMemoryDetail(pid int32) (core.MemoryDetail, error) {
+	var d core.MemoryDetail
+	f, err := os.Open(procPath(pid, "smaps_rollup"))
+	if err != nil {
+		return d, wrapProcErr(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// 格式: "Pss:                1234 kB"
+		key, rest, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		v := kb * 1024
+		switch key {
+		case "Rss":
+			d.RSS = v
+		case "Pss":
+			d.PSS = v
+		case "Private_Clean", "Private_Dirty":
+			d.USS += v
+		case "Shared_Clean", "Shared_Dirty":
+			d.Shared += v
+		case "Swap":
+			d.Swap = v
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return d, wrapProcErr(err)
+	}
+	return d, nil
+}
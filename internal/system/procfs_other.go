@@ -0,0 +1,41 @@
+//go:build !linux
+
+package system
+
+import "github.com/Microindole/quell/internal/core"
+
+func readEnviron(pid int32) ([]string, error) {
+	return nil, core.ErrNotSupported
+}
+
+func readOpenFiles(pid int32) ([]core.OpenFile, error) {
+	return nil, core.ErrNotSupported
+}
+
+func readLimits(pid int32) ([]core.Limit, error) {
+	return nil, core.ErrNotSupported
+}
+
+func readThreads(pid int32) ([]core.Thread, error) {
+	return nil, core.ErrNotSupported
+}
+
+func readFileRefs(pid int32) ([]core.FileRef, error) {
+	return nil, core.ErrNotSupported
+}
+
+func readMemoryMaps(pid int32) ([]core.MemoryMap, error) {
+	return nil, core.ErrNotSupported
+}
+
+func readMemoryDetail(pid int32) (core.MemoryDetail, error) {
+	return core.MemoryDetail{}, core.ErrNotSupported
+}
+
+func readResourceCounts(pid int32) (core.ResourceCounts, error) {
+	return core.ResourceCounts{}, core.ErrNotSupported
+}
+
+func readSessionInfo(pid int32) (core.SessionInfo, error) {
+	return core.SessionInfo{}, core.ErrNotSupported
+}
@@ -0,0 +1,34 @@
+package system
+
+import (
+	"time"
+
+	"github.com/Microindole/quell/internal/core"
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+// GetSystemStats 汇总整机信息，单项失败时对应字段保持零值
+func (l *LocalProvider) GetSystemStats() (core.SystemStats, error) {
+	var s core.SystemStats
+
+	if avg, err := load.Avg(); err == nil {
+		s.Load1, s.Load5, s.Load15 = avg.Load1, avg.Load5, avg.Load15
+	}
+	if up, err := host.Uptime(); err == nil {
+		s.Uptime = time.Duration(up) * time.Second
+	}
+	// interval 为 0 时与上一次调用比较，正好与心跳的刷新周期一致
+	if perCPU, err := cpu.Percent(0, true); err == nil {
+		s.PerCPU = perCPU
+	}
+	if vm, err := mem.VirtualMemory(); err == nil {
+		s.MemTotal, s.MemUsed = vm.Total, vm.Used
+	}
+	if sw, err := mem.SwapMemory(); err == nil {
+		s.SwapTotal, s.SwapUsed = sw.Total, sw.Used
+	}
+	return s, nil
+}
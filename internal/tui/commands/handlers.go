@@ -2,10 +2,16 @@ package commands
 
 import (
 	"fmt"
+	"os"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/Microindole/quell/internal/core"
+	"github.com/Microindole/quell/internal/export"
 	"github.com/Microindole/quell/internal/tui/pages"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -21,104 +27,341 @@ func QuitCmd(args []string, state *pages.SharedState) (pages.View, tea.Cmd) {
 	return nil, tea.Quit
 }
 
-// KillCmd 实现 /kill <pid>
+// KillCmd 实现 /kill [pid...]
 func KillCmd(args []string, state *pages.SharedState) (pages.View, tea.Cmd) {
+	return nil, operationCmd(state, pages.OpTerminate, args, "/kill [pid...]")
+}
+
+func PauseCmd(args []string, state *pages.SharedState) (pages.View, tea.Cmd) {
+	return nil, operationCmd(state, pages.OpSuspend, args, "/pause [pid...]")
+}
+
+func ResumeCmd(args []string, state *pages.SharedState) (pages.View, tea.Cmd) {
+	return nil, operationCmd(state, pages.OpResume, args, "/resume [pid...]")
+}
+
+// SignalCmd 实现 /signal <SIG> [pid...]
+func SignalCmd(args []string, state *pages.SharedState) (pages.View, tea.Cmd) {
+	const usage = "/signal <SIG> [pid...]"
 	if len(args) == 0 {
-		return nil, nil
-	} // 简化错误处理
-	pid, err := strconv.ParseInt(args[0], 10, 32)
+		return nil, usageCmd(usage)
+	}
+	sig, err := core.ParseSignal(args[0])
 	if err != nil {
-		return nil, nil
+		return nil, func() tea.Msg { return pages.ProcessActionMsg{Err: fmt.Errorf("%v (usage: %s)", err, usage)} }
 	}
+	return nil, operationCmd(state, pages.OpSignal(sig), args[1:], usage)
+}
 
-	cmd := func() tea.Msg {
-		err := state.Service.Kill(int32(pid), false)
-		return pages.ProcessActionMsg{Err: err, Action: "Killed"}
+// ReniceCmd 实现 /renice <nice> [-t] [pid...]
+func ReniceCmd(args []string, state *pages.SharedState) (pages.View, tea.Cmd) {
+	const usage = "/renice <nice> [-t] [pid...]"
+	if len(args) == 0 {
+		return nil, usageCmd(usage)
+	}
+	nice, err := core.ParseNice(args[0])
+	if err != nil {
+		return nil, func() tea.Msg { return pages.ProcessActionMsg{Err: fmt.Errorf("%v (usage: %s)", err, usage)} }
 	}
-	return nil, tea.Batch(pages.Pop(), cmd)
+	return nil, subtreeOperationCmd(state, pages.OpRenice(nice), args[1:], usage)
 }
 
-func PauseCmd(args []string, state *pages.SharedState) (pages.View, tea.Cmd) {
+// IONiceCmd 实现 /ionice <none|idle|be[/0-7]|rt[/0-7]> [-t] [pid...]
+func IONiceCmd(args []string, state *pages.SharedState) (pages.View, tea.Cmd) {
+	const usage = "/ionice <none|idle|be[/0-7]|rt[/0-7]> [-t] [pid...]"
 	if len(args) == 0 {
-		return nil, nil
+		return nil, usageCmd(usage)
 	}
-	pid, err := strconv.ParseInt(args[0], 10, 32)
+	prio, err := core.ParseIOPriority(args[0])
+	if err != nil {
+		return nil, func() tea.Msg { return pages.ProcessActionMsg{Err: fmt.Errorf("%v (usage: %s)", err, usage)} }
+	}
+	return nil, subtreeOperationCmd(state, pages.OpIONice(prio), args[1:], usage)
+}
+
+// AffinityCmd 实现 /affinity <cpus> [-a] [-t] [pid...]，例如 /affinity 0-3,8 1234
+// -a 对进程的所有线程生效，默认只设置主线程 (之后新建的线程会继承)
+func AffinityCmd(args []string, state *pages.SharedState) (pages.View, tea.Cmd) {
+	const usage = "/affinity <cpus> [-a] [-t] [pid...]"
+	if len(args) == 0 {
+		return nil, usageCmd(usage)
+	}
+	cpus, err := core.ParseCPUSet(args[0])
 	if err != nil {
-		return nil, nil
+		return nil, func() tea.Msg { return pages.ProcessActionMsg{Err: fmt.Errorf("%v (usage: %s)", err, usage)} }
+	}
+	threads := false
+	var rest []string
+	for _, a := range args[1:] {
+		if a == "-a" || a == "--threads" {
+			threads = true
+			continue
+		}
+		rest = append(rest, a)
 	}
+	return nil, subtreeOperationCmd(state, pages.OpAffinity(cpus, threads), rest, usage)
+}
 
-	cmd := func() tea.Msg {
-		err := state.Service.Suspend(int32(pid))
-		return pages.ProcessActionMsg{Err: err, Action: "Suspended"}
+// subtreeOperationCmd 在 operationCmd 的基础上支持 -t：连同目标的全部子孙进程一起批量执行
+func subtreeOperationCmd(state *pages.SharedState, op pages.Operation, args []string, usage string) tea.Cmd {
+	var rest []string
+	subtree := false
+	for _, a := range args {
+		if a == "-t" || a == "--tree" {
+			subtree = true
+			continue
+		}
+		rest = append(rest, a)
+	}
+	if !subtree {
+		return operationCmd(state, op, rest, usage)
 	}
-	return nil, tea.Batch(pages.Pop(), cmd)
+	if len(rest) == 0 {
+		surface := state.Service.Surface()
+		return func() tea.Msg { return pages.BatchSelectionMsg{Op: op, Subtree: true, Surface: surface} }
+	}
+	pids, ok := parsePids(rest)
+	if !ok {
+		return usageCmd(usage)
+	}
+	procs, missing := lookupPids(state, pids)
+	if len(missing) > 0 {
+		return missingCmd(missing)
+	}
+	return pages.ConfirmBatch(state, op, pages.WithDescendants(state.Service, procs))
 }
 
-func ResumeCmd(args []string, state *pages.SharedState) (pages.View, tea.Cmd) {
+// operationCmd /kill、/pause、/resume、/signal 的共同逻辑：
+// 一个 PID 时直接执行，多个 PID 时批量执行并打开结果页，不带 PID 时针对列表中勾选的进程
+func operationCmd(state *pages.SharedState, op pages.Operation, args []string, usage string) tea.Cmd {
 	if len(args) == 0 {
-		return nil, nil
+		surface := state.Service.Surface()
+		return func() tea.Msg { return pages.BatchSelectionMsg{Op: op, Surface: surface} }
 	}
-	pid, err := strconv.ParseInt(args[0], 10, 32)
-	if err != nil {
-		return nil, nil
+	pids, ok := parsePids(args)
+	if !ok {
+		return usageCmd(usage)
+	}
+
+	if len(pids) == 1 {
+		cmd := func() tea.Msg { return op.Run(state.Service, pids[0]) }
+		if !op.Guarded {
+			return cmd
+		}
+		return guarded(state, pids[0], cmd)
+	}
+
+	procs, missing := lookupPids(state, pids)
+	if len(missing) > 0 {
+		return missingCmd(missing)
+	}
+	run := pages.StartBatch(state, op, procs)
+	if !op.Guarded {
+		return run
 	}
+	return pages.GuardedAction(state, procs, "", run)
+}
 
-	cmd := func() tea.Msg {
-		err := state.Service.Resume(int32(pid))
-		return pages.ProcessActionMsg{Err: err, Action: "Resumed"}
+// lookupPids 按最近一次扫描查找进程，返回找到的进程与不存在的 PID
+func lookupPids(state *pages.SharedState, pids []int32) ([]core.Process, []string) {
+	procs := make([]core.Process, 0, len(pids))
+	var missing []string
+	for _, pid := range pids {
+		p, ok := state.Service.Lookup(pid)
+		if !ok {
+			missing = append(missing, strconv.Itoa(int(pid)))
+			continue
+		}
+		procs = append(procs, p)
 	}
-	return nil, tea.Batch(pages.Pop(), cmd)
+	return procs, missing
 }
 
-// PKillCmd 实现批量查杀
-// 用法：/pkill chrome (杀掉所有名字里包含 chrome 的进程)
+func missingCmd(missing []string) tea.Cmd {
+	return func() tea.Msg {
+		return pages.ProcessActionMsg{Err: fmt.Errorf("no such process: %s", strings.Join(missing, ", "))}
+	}
+}
+
+// pkillOptions /pkill 的匹配条件
+type pkillOptions struct {
+	pattern  string
+	exact    bool // -x: 完全相等 (区分大小写)
+	regex    bool // -r: 正则
+	cmdline  bool // -f: 匹配完整命令行而不只是进程名
+	children bool // -c: 连同匹配进程的所有子孙进程
+	user     string
+	sig      syscall.Signal
+}
+
+const pkillUsage = "usage: /pkill [-x|-r] [-f] [-u user] [-s SIG|-SIG] [-c] <pattern>"
+
+func parsePKillArgs(args []string) (pkillOptions, error) {
+	opts := pkillOptions{sig: syscall.SIGTERM}
+	var words []string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if len(words) > 0 || !strings.HasPrefix(a, "-") {
+			words = append(words, a)
+			continue
+		}
+		switch a {
+		case "--":
+			words = append(words, args[i+1:]...)
+			i = len(args)
+		case "-x", "--exact":
+			opts.exact = true
+		case "-r", "--regex":
+			opts.regex = true
+		case "-f", "--cmdline":
+			opts.cmdline = true
+		case "-c", "--children":
+			opts.children = true
+		case "-u", "--user", "-s", "--signal":
+			if i+1 >= len(args) {
+				return opts, fmt.Errorf("%s needs a value", a)
+			}
+			i++
+			if a == "-u" || a == "--user" {
+				opts.user = args[i]
+				break
+			}
+			sig, err := core.ParseSignal(args[i])
+			if err != nil {
+				return opts, err
+			}
+			opts.sig = sig
+		default:
+			// -9、-KILL、-HUP 这种 kill 风格的写法
+			sig, err := core.ParseSignal(a)
+			if err != nil {
+				return opts, fmt.Errorf("unknown option %s", a)
+			}
+			opts.sig = sig
+		}
+	}
+	opts.pattern = strings.Join(words, " ")
+	if opts.pattern == "" {
+		return opts, fmt.Errorf("missing pattern")
+	}
+	if opts.exact && opts.regex {
+		return opts, fmt.Errorf("-x and -r cannot be combined")
+	}
+	return opts, nil
+}
+
+// matcher 根据选项构造匹配函数，正则无效时报错
+func (o pkillOptions) matcher() (func(p core.Process) bool, error) {
+	field := func(p core.Process) string {
+		if o.cmdline && p.Cmdline != "" {
+			return p.Cmdline
+		}
+		return p.Name
+	}
+	switch {
+	case o.regex:
+		re, err := regexp.Compile(o.pattern)
+		if err != nil {
+			return nil, err
+		}
+		return func(p core.Process) bool { return re.MatchString(field(p)) }, nil
+	case o.exact:
+		return func(p core.Process) bool { return field(p) == o.pattern }, nil
+	default:
+		lower := strings.ToLower(o.pattern)
+		return func(p core.Process) bool { return strings.Contains(strings.ToLower(field(p)), lower) }, nil
+	}
+}
+
+func (o pkillOptions) describe() string {
+	how := "name contains"
+	switch {
+	case o.regex:
+		how = "name matches"
+	case o.exact:
+		how = "name is"
+	}
+	if o.cmdline {
+		how = strings.Replace(how, "name", "cmdline", 1)
+	}
+	desc := fmt.Sprintf("%s '%s'", how, o.pattern)
+	if o.user != "" {
+		desc += ", user " + o.user
+	}
+	if o.children {
+		desc += ", with children"
+	}
+	return desc
+}
+
+// PKillCmd 实现 /pkill [options] <pattern>
+// 不再直接查杀：先列出匹配的进程 (受保护的默认不勾选)，可逐个取消勾选，按 Enter 确认后发送信号
 func PKillCmd(args []string, state *pages.SharedState) (pages.View, tea.Cmd) {
-	// 1. 校验参数
-	if len(args) == 0 {
+	opts, err := parsePKillArgs(args)
+	if err == nil {
+		_, err = opts.matcher()
+	}
+	if err != nil {
 		return nil, func() tea.Msg {
-			return pages.ProcessActionMsg{Err: fmt.Errorf("usage: /pkill <name>")}
+			return pages.ProcessActionMsg{Err: fmt.Errorf("%v (%s)", err, pkillUsage)}
 		}
 	}
-	target := args[0] // 简单的取第一个参数，例如 "chrome"
+	match, _ := opts.matcher()
 
-	// 2. 执行逻辑
-	cmd := func() tea.Msg {
-		// 获取最新进程列表
+	load := func() ([]pages.PickEntry, string, error) {
 		procs, err := state.Service.GetProcesses()
 		if err != nil {
-			return pages.ProcessActionMsg{Err: err}
+			return nil, "", err
+		}
+		// quell 自己永远不在候选中
+		self := int32(os.Getpid())
+		byPID := make(map[int32]core.Process, len(procs))
+		children := make(map[int32][]int32)
+		for _, p := range procs {
+			byPID[p.PID] = p
+			children[p.PPID] = append(children[p.PPID], p.PID)
 		}
 
-		count := 0
-		targetLower := strings.ToLower(target)
-
-		// 遍历并查杀
+		details := make(map[int32]string)
+		var queue []int32
 		for _, p := range procs {
-			// 使用 Contains 做模糊匹配 (不区分大小写)
-			if strings.Contains(strings.ToLower(p.Name), targetLower) {
-				// 执行查杀 (忽略单个失败，只统计成功数)
-				if err := state.Service.Kill(p.PID, false); err == nil {
-					count++
+			if p.PID == self || (opts.user != "" && p.User != opts.user) || !match(p) {
+				continue
+			}
+			details[p.PID] = p.Cmdline
+			queue = append(queue, p.PID)
+		}
+		matched := len(details)
+		if opts.children {
+			for len(queue) > 0 {
+				parent := queue[0]
+				queue = queue[1:]
+				for _, pid := range children[parent] {
+					if _, seen := details[pid]; seen || pid == self || pid == parent {
+						continue
+					}
+					details[pid] = fmt.Sprintf("child of %s (%d) | %s", byPID[parent].Name, parent, byPID[pid].Cmdline)
+					queue = append(queue, pid)
 				}
 			}
 		}
 
-		// 3. 反馈结果
-		if count == 0 {
-			return pages.ProcessActionMsg{
-				Err: fmt.Errorf("no processes found matching '%s'", target),
-			}
+		entries := make([]pages.PickEntry, 0, len(details))
+		for pid, detail := range details {
+			entries = append(entries, pages.PickEntry{Process: byPID[pid], Detail: detail})
 		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Process.PID < entries[j].Process.PID })
 
-		return pages.ProcessActionMsg{
-			// 配合 ListView 的 "successfully." 后缀，这里拼接成句子
-			// 例如: "Killed 5 processes matching 'chrome'" -> "... successfully."
-			Action: fmt.Sprintf("Killed %d processes matching '%s'", count, target),
+		note := fmt.Sprintf("%d matched", matched)
+		if extra := len(entries) - matched; extra > 0 {
+			note += fmt.Sprintf(" + %d children", extra)
 		}
+		return entries, note, nil
 	}
 
-	// 关闭输入框并执行
-	return nil, tea.Batch(pages.Pop(), cmd)
+	view := pages.NewPickView(state, fmt.Sprintf("pkill: %s → %s", opts.describe(), core.SignalName(opts.sig)), load)
+	view.SetSignal(opts.sig)
+	return view, nil
 }
 
 // PortCmd 实现 /port 8080
@@ -136,10 +379,262 @@ func PortCmd(args []string, state *pages.SharedState) (pages.View, tea.Cmd) {
 		}
 	}
 
-	return nil, tea.Batch(
-		pages.Pop(),
-		tea.Tick(10*time.Millisecond, func(t time.Time) tea.Msg {
-			return pages.SetFilterMsg(":" + portStr)
-		}),
-	)
+	return nil, func() tea.Msg { return pages.SetFilterMsg(":" + portStr) }
+}
+
+// FilterCmd 实现 /filter <text>：设置列表的过滤条件，不带参数时清空
+func FilterCmd(args []string, state *pages.SharedState) (pages.View, tea.Cmd) {
+	filter := strings.Join(args, " ")
+	return nil, func() tea.Msg { return pages.SetFilterMsg(filter) }
+}
+
+const selectUsage = "/select <pid>... | <query> | all | invert | tree [pid] | siblings [pid] | save|recall|drop <name> | saved | clear"
+
+// SelectCmd 实现 /select：按 PID、查询条件、子树等加入多选集合，或保存、取回命名的选择
+//
+//	/select 1234 5678         加入指定 PID
+//	/select nginx user=www    加入满足条件的全部进程 (name= user= cmd= status= port= owner= ppid= cpu>N mem>SIZE)
+//	/select all | invert      当前 (过滤后) 列表中的全部进程 / 反选
+//	/select tree [pid]        进程及其全部子孙进程，不带 PID 时使用光标所在的进程
+//	/select siblings [pid]    同名的兄弟进程
+//	/select save <name>       保存当前选择，recall 并入当前选择，drop 删除，saved 列出
+//	/select clear             清空
+func SelectCmd(args []string, state *pages.SharedState) (pages.View, tea.Cmd) {
+	if len(args) == 0 {
+		return nil, usageCmd(selectUsage)
+	}
+	sub, rest := args[0], args[1:]
+	msg := pages.SelectMsg{}
+	switch sub {
+	case "clear", "none":
+		return nil, func() tea.Msg { return pages.ClearSelectionMsg{} }
+	case "all", "visible":
+		msg.Op = pages.SelectVisible
+	case "invert":
+		msg.Op = pages.SelectInvert
+	case "tree", "subtree", "siblings":
+		msg.Op = pages.SelectSubtree
+		if sub == "siblings" {
+			msg.Op = pages.SelectSiblings
+		}
+		pids, ok := parsePids(rest)
+		if !ok {
+			return nil, usageCmd(selectUsage)
+		}
+		msg.PIDs = pids
+	case "save", "recall", "load", "drop":
+		if len(rest) != 1 {
+			return nil, usageCmd(fmt.Sprintf("/select %s <name>", sub))
+		}
+		msg.Name = rest[0]
+		switch sub {
+		case "save":
+			msg.Op = pages.SelectSave
+		case "drop":
+			msg.Op = pages.SelectDrop
+		default:
+			msg.Op = pages.SelectRecall
+		}
+	case "saved":
+		msg.Op = pages.SelectSaved
+	default:
+		// 全是数字时按 PID 处理，否则作为查询条件
+		if pids, ok := parsePids(args); ok {
+			msg.Op, msg.PIDs = pages.SelectPIDs, pids
+			break
+		}
+		q, err := core.ParseProcessQuery(args)
+		if err != nil {
+			return nil, func() tea.Msg { return pages.ProcessActionMsg{Err: err} }
+		}
+		msg.Op, msg.Query = pages.SelectQuery, q
+	}
+	return nil, func() tea.Msg { return msg }
+}
+
+// parsePids 解析全部参数为 PID
+func parsePids(args []string) ([]int32, bool) {
+	pids := make([]int32, 0, len(args))
+	for _, a := range args {
+		pid, ok := parsePidArg([]string{a})
+		if !ok {
+			return nil, false
+		}
+		pids = append(pids, pid)
+	}
+	return pids, true
+}
+
+// DeletedCmd 实现 /deleted：列出已删除但仍被打开的文件
+func DeletedCmd(args []string, state *pages.SharedState) (pages.View, tea.Cmd) {
+	return pages.NewDeletedFilesView(state), nil
+}
+
+// LogCmd 实现 /log [pid=N] [surface=api] [action=kill] [since=1h] [failed] [name]：浏览审计日志
+func LogCmd(args []string, state *pages.SharedState) (pages.View, tea.Cmd) {
+	filter, err := core.ParseAuditFilter(args)
+	if err != nil {
+		return nil, func() tea.Msg { return pages.ProcessActionMsg{Err: err} }
+	}
+	return pages.NewAuditView(state, filter), nil
+}
+
+// WhoHasCmd 实现 /who-has <path>：找出占用文件、目录或挂载点的进程
+func WhoHasCmd(args []string, state *pages.SharedState) (pages.View, tea.Cmd) {
+	if len(args) == 0 {
+		return nil, func() tea.Msg {
+			return pages.ProcessActionMsg{Err: fmt.Errorf("usage: /who-has <path>")}
+		}
+	}
+	path := strings.Join(args, " ")
+
+	load := func() ([]pages.PickEntry, string, error) {
+		holders, skipped, err := state.Service.WhoHas(path)
+		if err != nil {
+			return nil, "", err
+		}
+		entries := make([]pages.PickEntry, len(holders))
+		for i, h := range holders {
+			detail := fmt.Sprintf("[%s] %s", h.How(), h.Refs[0])
+			if len(h.Refs) > 1 {
+				detail += fmt.Sprintf(" (+%d more)", len(h.Refs)-1)
+			}
+			entries[i] = pages.PickEntry{Process: h.Process, Detail: detail}
+		}
+		note := ""
+		if skipped > 0 {
+			note = fmt.Sprintf("%d processes skipped (permission denied)", skipped)
+		}
+		return entries, note, nil
+	}
+	return pages.NewPickView(state, "Who has "+path, load), nil
+}
+
+// LibCmd 实现 /lib <name> 与 /lib --deleted
+// 打完 openssl/glibc 补丁后，用来找出仍在使用旧库、需要重启的进程
+func LibCmd(args []string, state *pages.SharedState) (pages.View, tea.Cmd) {
+	var names []string
+	deletedOnly := false
+	for _, a := range args {
+		if a == "--deleted" || a == "-d" {
+			deletedOnly = true
+			continue
+		}
+		names = append(names, a)
+	}
+	name := strings.Join(names, " ")
+	if name == "" && !deletedOnly {
+		return nil, func() tea.Msg {
+			return pages.ProcessActionMsg{Err: fmt.Errorf("usage: /lib <name> | /lib --deleted [name]")}
+		}
+	}
+
+	title := "Processes mapping " + name
+	if deletedOnly {
+		title = strings.TrimSpace("Processes mapping deleted libraries " + name)
+	}
+
+	load := func() ([]pages.PickEntry, string, error) {
+		users, skipped, err := state.Service.FindLibraryUsers(name, deletedOnly)
+		if err != nil {
+			return nil, "", err
+		}
+		entries := make([]pages.PickEntry, len(users))
+		for i, u := range users {
+			var libs []string
+			for _, m := range u.Libraries {
+				lib := m.Path
+				if m.Deleted {
+					lib += " (deleted)"
+				}
+				libs = append(libs, lib)
+			}
+			entries[i] = pages.PickEntry{Process: u.Process, Detail: strings.Join(libs, ", ")}
+		}
+		note := ""
+		if skipped > 0 {
+			note = fmt.Sprintf("%d processes skipped (permission denied)", skipped)
+		}
+		return entries, note, nil
+	}
+	return pages.NewPickView(state, title, load), nil
+}
+
+// ExportCmd 实现 /export [file] [--format json|csv|md] [--columns a,b] [--tree|--flat]
+// 导出 ListView 当前显示的 (过滤后的) 列表，格式默认由扩展名决定
+func ExportCmd(args []string, state *pages.SharedState) (pages.View, tea.Cmd) {
+	usage := func(err error) (pages.View, tea.Cmd) {
+		return nil, func() tea.Msg {
+			return pages.ProcessActionMsg{Err: fmt.Errorf("%v (usage: /export [file] [--format json|csv|md] [--columns a,b] [--tree|--flat])", err)}
+		}
+	}
+
+	msg := pages.ExportMsg{}
+	var format string
+	var err error
+	for i := 0; i < len(args); i++ {
+		switch a := args[i]; a {
+		case "--tree", "--flat":
+			tree := a == "--tree"
+			msg.Tree = &tree
+		case "--format", "--columns":
+			if i+1 >= len(args) {
+				return usage(fmt.Errorf("%s needs a value", a))
+			}
+			i++
+			if a == "--format" {
+				format = args[i]
+			} else if msg.Options.Columns, err = export.ParseColumns(args[i]); err != nil {
+				return usage(err)
+			}
+		default:
+			if strings.HasPrefix(a, "-") {
+				return usage(fmt.Errorf("unknown option %s", a))
+			}
+			msg.Path = a
+		}
+	}
+
+	if format != "" {
+		if msg.Options.Format, err = export.ParseFormat(format); err != nil {
+			return usage(err)
+		}
+	}
+	if msg.Path == "" {
+		ext := string(msg.Options.Format)
+		if ext == "" {
+			ext = string(export.FormatJSON)
+		}
+		msg.Path = fmt.Sprintf("quell-%s.%s", time.Now().Format("20060102-150405"), ext)
+	}
+
+	return nil, func() tea.Msg { return msg }
+}
+
+// guarded 受保护的进程先弹出确认框，其余直接执行
+func guarded(state *pages.SharedState, pid int32, cmd tea.Cmd) tea.Cmd {
+	p, ok := state.Service.Lookup(pid)
+	if !ok {
+		return cmd
+	}
+	return pages.GuardedAction(state, []core.Process{p}, "", cmd)
+}
+
+// parsePidArg 解析命令的第一个参数为 PID
+func parsePidArg(args []string) (int32, bool) {
+	if len(args) == 0 {
+		return 0, false
+	}
+	pid, err := strconv.ParseInt(args[0], 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return int32(pid), true
+}
+
+// usageCmd 参数不对时把用法作为错误反馈
+func usageCmd(usage string) tea.Cmd {
+	return func() tea.Msg {
+		return pages.ProcessActionMsg{Err: fmt.Errorf("usage: %s", usage)}
+	}
 }
@@ -16,8 +16,19 @@ func RegisterAll(registry map[string]pages.CommandFunc) {
 	registry["/pause"] = PauseCmd
 	registry["/cont"] = ResumeCmd
 	registry["/resume"] = ResumeCmd
+	registry["/signal"] = SignalCmd
+	registry["/renice"] = ReniceCmd
+	registry["/ionice"] = IONiceCmd
+	registry["/affinity"] = AffinityCmd
 
 	registry["/pkill"] = PKillCmd
 	registry["/killall"] = PKillCmd
 	registry["/port"] = PortCmd
+	registry["/deleted"] = DeletedCmd
+	registry["/who-has"] = WhoHasCmd
+	registry["/lib"] = LibCmd
+	registry["/export"] = ExportCmd
+	registry["/filter"] = FilterCmd
+	registry["/select"] = SelectCmd
+	registry["/log"] = LogCmd
 }
@@ -0,0 +1,323 @@
+package components
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Point 是带时间戳的采样点
+type Point struct {
+	T time.Time
+	V float64
+}
+
+// TimeSeries 按时间保存采样点，超过 Retention 的旧数据会被丢弃
+type TimeSeries struct {
+	Points    []Point
+	Retention time.Duration
+}
+
+func NewTimeSeries(retention time.Duration) *TimeSeries {
+	return &TimeSeries{Retention: retention}
+}
+
+// Add 追加一个采样点并清理过期数据
+// 时间倒退时 (回放中向后跳转) 先丢弃晚于 t 的点，保持按时间排序
+func (ts *TimeSeries) Add(t time.Time, v float64) {
+	for len(ts.Points) > 0 && ts.Points[len(ts.Points)-1].T.After(t) {
+		ts.Points = ts.Points[:len(ts.Points)-1]
+	}
+	ts.Points = append(ts.Points, Point{T: t, V: v})
+	cutoff := t.Add(-ts.Retention)
+	i := 0
+	for i < len(ts.Points) && ts.Points[i].T.Before(cutoff) {
+		i++
+	}
+	ts.Points = ts.Points[i:]
+}
+
+// Backfill 把更早的历史数据 (按时间升序) 补到现有数据之前
+// 与现有数据重叠的部分会被忽略
+func (ts *TimeSeries) Backfill(points []Point) {
+	var older []Point
+	for _, p := range points {
+		if len(ts.Points) > 0 && !p.T.Before(ts.Points[0].T) {
+			break
+		}
+		older = append(older, p)
+	}
+	ts.Points = append(older, ts.Points...)
+}
+
+// Series 是图表中的一条曲线
+type Series struct {
+	Name      string
+	Points    []Point
+	Color     lipgloss.Color
+	Secondary bool // 使用右侧的第二 Y 轴 (单位不同的曲线叠加时使用)
+}
+
+// Axis 描述一条 Y 轴的刻度方式
+type Axis struct {
+	Auto    bool    // true: 根据数据自动缩放；false: 使用 Min/Max
+	Min     float64 // 固定模式的下限，自动模式下也作为下限
+	Max     float64 // 固定模式的上限
+	MinSpan float64 // 自动模式下的最小跨度，避免 0.5% 的抖动被放大成尖峰
+	Format  func(float64) string
+}
+
+func (a Axis) format(v float64) string {
+	if a.Format != nil {
+		return a.Format(v)
+	}
+	return fmt.Sprintf("%.0f", v)
+}
+
+// Chart 多行 braille 折线图，带 Y 轴刻度与时间轴
+// 每个字符包含 2x4 个点，因此横向分辨率是 Width*2，纵向是 Height*4
+type Chart struct {
+	Width     int // 绘图区宽度 (字符)，不含坐标轴
+	Height    int // 绘图区高度 (行)
+	Window    time.Duration
+	Primary   Axis
+	Secondary Axis
+}
+
+// braille 点位：dots[y][x]，y 从上到下
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+var axisStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262"))
+
+// Render 渲染截止到 now 的 Window 时间范围内的数据
+func (c *Chart) Render(now time.Time, series ...Series) string {
+	w, h := c.Width, c.Height
+	if w < 2 || h < 1 || c.Window <= 0 {
+		return ""
+	}
+	dotsW, dotsH := w*2, h*4
+	start := now.Add(-c.Window)
+	bucket := c.Window / time.Duration(dotsW)
+
+	cells := make([][]rune, h)
+	colors := make([][]lipgloss.Color, h)
+	for i := range cells {
+		cells[i] = make([]rune, w)
+		colors[i] = make([]lipgloss.Color, w)
+	}
+
+	primMin, primMax := c.Primary.bounds(series, false, start)
+	secMin, secMax := c.Secondary.bounds(series, true, start)
+	hasSecondary := false
+
+	for _, s := range series {
+		lo, hi := primMin, primMax
+		if s.Secondary {
+			lo, hi = secMin, secMax
+			hasSecondary = true
+		}
+		cols := resample(s.Points, start, bucket, dotsW)
+
+		prevY := -1
+		for x, v := range cols {
+			if math.IsNaN(v) {
+				prevY = -1
+				continue
+			}
+			y := dotsH - 1 - int((v-lo)/(hi-lo)*float64(dotsH-1)+0.5)
+			y = clamp(y, 0, dotsH-1)
+
+			// 与前一个点垂直相连，形成连续的折线
+			from, to := y, y
+			if prevY >= 0 {
+				from, to = minInt(prevY, y), maxInt(prevY, y)
+			}
+			for yy := from; yy <= to; yy++ {
+				row, col := yy/4, x/2
+				cells[row][col] |= brailleDots[yy%4][x%2]
+				colors[row][col] = s.Color
+			}
+			prevY = y
+		}
+	}
+
+	// Y 轴标签只标注最大值与最小值
+	maxLabel, minLabel := c.Primary.format(primMax), c.Primary.format(primMin)
+	labelW := maxInt(lipgloss.Width(maxLabel), lipgloss.Width(minLabel))
+	secMaxLabel, secMinLabel := c.Secondary.format(secMax), c.Secondary.format(secMin)
+
+	var sb strings.Builder
+	for row := 0; row < h; row++ {
+		label := ""
+		switch row {
+		case 0:
+			label = maxLabel
+		case h - 1:
+			label = minLabel
+		}
+		sb.WriteString(axisStyle.Render(fmt.Sprintf("%*s ┤", labelW, label)))
+		for col := 0; col < w; col++ {
+			r := 0x2800 + cells[row][col]
+			if cells[row][col] == 0 {
+				sb.WriteRune(r)
+				continue
+			}
+			sb.WriteString(lipgloss.NewStyle().Foreground(colors[row][col]).Render(string(r)))
+		}
+		if hasSecondary {
+			right := ""
+			switch row {
+			case 0:
+				right = secMaxLabel
+			case h - 1:
+				right = secMinLabel
+			}
+			sb.WriteString(axisStyle.Render("├ " + right))
+		}
+		sb.WriteString("\n")
+	}
+
+	// 时间轴
+	sb.WriteString(axisStyle.Render(strings.Repeat(" ", labelW+1) + "└" + strings.Repeat("─", w)))
+	sb.WriteString("\n")
+	left := "-" + FormatWindow(c.Window)
+	mid := "-" + FormatWindow(c.Window/2)
+	right := "now"
+	gap := w - len(left) - len(mid) - len(right)
+	if gap >= 2 {
+		lpad := gap / 2
+		sb.WriteString(axisStyle.Render(strings.Repeat(" ", labelW+2) + left +
+			strings.Repeat(" ", lpad) + mid + strings.Repeat(" ", gap-lpad) + right))
+	} else {
+		sb.WriteString(axisStyle.Render(strings.Repeat(" ", labelW+2) + left +
+			strings.Repeat(" ", maxInt(1, w-len(left)-len(right))) + right))
+	}
+
+	return sb.String()
+}
+
+// Legend 渲染各曲线的图例
+func Legend(series ...Series) string {
+	var parts []string
+	for _, s := range series {
+		parts = append(parts, lipgloss.NewStyle().Foreground(s.Color).Render("⣿ "+s.Name))
+	}
+	return strings.Join(parts, "  ")
+}
+
+// bounds 计算某条 Y 轴的范围
+func (a Axis) bounds(series []Series, secondary bool, start time.Time) (float64, float64) {
+	lo, hi := a.Min, a.Max
+	if a.Auto {
+		hi = lo
+		for _, s := range series {
+			if s.Secondary != secondary {
+				continue
+			}
+			for _, p := range s.Points {
+				if !p.T.Before(start) && p.V > hi {
+					hi = p.V
+				}
+			}
+		}
+		if hi-lo < a.MinSpan {
+			hi = lo + a.MinSpan
+		}
+	}
+	if hi <= lo {
+		hi = lo + 1
+	}
+	return lo, hi
+}
+
+// resample 把采样点按时间分桶，每个桶取最大值；没有数据的桶为 NaN
+func resample(points []Point, start time.Time, bucket time.Duration, n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	if bucket <= 0 {
+		return out
+	}
+	for _, p := range points {
+		if p.T.Before(start) {
+			continue
+		}
+		i := int(p.T.Sub(start) / bucket)
+		if i >= n {
+			i = n - 1
+		}
+		if math.IsNaN(out[i]) || p.V > out[i] {
+			out[i] = p.V
+		}
+	}
+	// 采样间隔大于桶宽时 (例如 2 秒心跳、1 分钟窗口) 用前一个值填补空隙
+	last := math.NaN()
+	lastIdx := -1
+	for i, v := range out {
+		if !math.IsNaN(v) {
+			if lastIdx >= 0 && i-lastIdx <= maxGap(bucket) {
+				for j := lastIdx + 1; j < i; j++ {
+					out[j] = last
+				}
+			}
+			last, lastIdx = v, i
+		}
+	}
+	return out
+}
+
+// maxGap 允许填补的最大空隙 (桶数)，超过则认为数据中断
+func maxGap(bucket time.Duration) int {
+	g := int(30 * time.Second / bucket)
+	if g < 1 {
+		g = 1
+	}
+	return g
+}
+
+// FormatWindow 把时间窗口格式化为 "10m" / "1h" 这样的短标签
+func FormatWindow(d time.Duration) string {
+	switch {
+	case d >= time.Hour && d%time.Hour == 0:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d >= time.Minute && d%time.Minute == 0:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d >= time.Minute:
+		return fmt.Sprintf("%.1fm", d.Minutes())
+	default:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,55 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Gauge 是 htop 风格的水平进度条：Label [|||||     text]
+// 与 Sparkline 一样只负责渲染，不持有数据
+type Gauge struct {
+	LabelStyle lipgloss.Style
+	BarStyle   lipgloss.Style
+	HighStyle  lipgloss.Style // 超过 HighMark 时使用
+	HighMark   float64
+}
+
+func NewGauge(bar lipgloss.Style) *Gauge {
+	return &Gauge{
+		LabelStyle: lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4")).Bold(true),
+		BarStyle:   bar,
+		HighStyle:  lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F5F")),
+		HighMark:   0.9,
+	}
+}
+
+// Render 渲染一个总宽度为 width 的进度条，ratio 取值 0-1，text 右对齐显示在条内
+func (g *Gauge) Render(label string, ratio float64, text string, width int) string {
+	if ratio < 0 {
+		ratio = 0
+	}
+	if ratio > 1 {
+		ratio = 1
+	}
+
+	// 除去 label、空格与两侧的方括号
+	inner := width - lipgloss.Width(label) - 3
+	if inner < 1 {
+		inner = 1
+	}
+	if len(text) > inner {
+		text = ""
+	}
+
+	filled := int(ratio*float64(inner) + 0.5)
+	bar := []rune(strings.Repeat("|", filled) + strings.Repeat(" ", inner-filled))
+	// 文字覆盖在条的右侧
+	copy(bar[inner-len(text):], []rune(text))
+
+	style := g.BarStyle
+	if ratio >= g.HighMark {
+		style = g.HighStyle
+	}
+	return g.LabelStyle.Render(label) + " [" + style.Render(string(bar)) + "]"
+}
@@ -0,0 +1,256 @@
+package tui
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Microindole/quell/internal/config"
+	"github.com/Microindole/quell/internal/core"
+	"github.com/Microindole/quell/internal/tui/pages"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// controlTimeout 等待命令结果的上限，超时后告诉调用方命令已提交但结果未知
+const controlTimeout = 10 * time.Second
+
+// ControlReply 控制 socket 的应答，每条命令对应一行 JSON
+type ControlReply struct {
+	OK      bool   `json:"ok"`
+	Message string `json:"message,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ControlSocketPath 返回指定 TUI 进程的控制 socket 路径 (~/.quell/run/<pid>.sock)
+func ControlSocketPath(pid int) string {
+	return filepath.Join(config.RunDir(), fmt.Sprintf("%d.sock", pid))
+}
+
+// controlRequest 由控制 socket 注入到 Model 的命令
+type controlRequest struct {
+	line     string
+	reporter *reporter
+}
+
+// controlForwardMsg 命令产生的普通消息 (过滤、选中、导出...)，
+// 由 Model 投递给对应页面后继续跟踪页面返回的结果
+type controlForwardMsg struct {
+	msg      tea.Msg
+	reporter *reporter
+}
+
+// reporter 跟踪一条命令派生出的所有 tea.Cmd，
+// 遇到第一个 ProcessActionMsg 时把它作为结果返回；全部执行完仍没有结果则返回 ok
+type reporter struct {
+	once    sync.Once
+	pending int32
+	reply   chan ControlReply
+}
+
+func newReporter() *reporter {
+	return &reporter{reply: make(chan ControlReply, 1)}
+}
+
+func (r *reporter) done(reply ControlReply) {
+	r.once.Do(func() { r.reply <- reply })
+}
+
+func (r *reporter) fail(err error) {
+	r.done(ControlReply{Error: err.Error()})
+}
+
+// report 把操作结果作为应答；演练模式的拦截不算失败
+func (r *reporter) report(m pages.ProcessActionMsg) {
+	switch {
+	case errors.Is(m.Err, core.ErrDryRun):
+		r.done(ControlReply{OK: true, Message: m.Err.Error()})
+	case m.Err != nil:
+		r.fail(m.Err)
+	case m.Note != "":
+		r.done(ControlReply{OK: true, Message: m.Action + " (note: " + m.Note + ")"})
+	default:
+		r.done(ControlReply{OK: true, Message: m.Action})
+	}
+}
+
+// release 一个被跟踪的 Cmd 执行完毕
+func (r *reporter) release() {
+	if atomic.AddInt32(&r.pending, -1) == 0 {
+		r.done(ControlReply{OK: true, Message: "ok"})
+	}
+}
+
+// track 包装 cmd 以便捕获结果；forward 为 true 时普通消息改由 Model 转发，
+// 这样页面处理消息后返回的 Cmd (例如导出) 也能被跟踪到
+func (r *reporter) track(cmd tea.Cmd, forward bool) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	atomic.AddInt32(&r.pending, 1)
+	return func() tea.Msg {
+		msg := cmd()
+		switch m := msg.(type) {
+		case pages.ProcessActionMsg:
+			r.report(m)
+			r.release()
+			return m
+		case tea.BatchMsg:
+			cmds := make(tea.BatchMsg, 0, len(m))
+			for _, c := range m {
+				cmds = append(cmds, r.track(c, forward))
+			}
+			r.release()
+			return cmds
+		case tea.QuitMsg:
+			r.done(ControlReply{OK: true, Message: "quitting"})
+			return m
+		case nil:
+			r.release()
+			return nil
+		case pages.PushViewMsg:
+			// 受保护的进程需要在 TUI 里输入确认，结果无法同步返回
+			switch v := m.View.(type) {
+			case *pages.ConfirmDialog, *pages.ActionDialog:
+				r.done(ControlReply{OK: true, Message: "waiting for confirmation in the TUI"})
+			case *pages.BatchView:
+				// 批量操作执行完才打开结果页，以汇总作为应答
+				r.report(v.Summary())
+			}
+			r.release()
+			return m
+		case pages.PopViewMsg, pages.ReplaceViewMsg:
+			// 页面栈操作仍交给 Model 本身
+			r.release()
+			return m
+		}
+		if forward {
+			return controlForwardMsg{msg: msg, reporter: r}
+		}
+		r.release()
+		return msg
+	}
+}
+
+// ControlServer 每个 TUI 会话一个的 Unix 控制 socket
+// 每行一条命令 (与命令模式相同，例如 "filter node")，应答一行 JSON
+type ControlServer struct {
+	ln   net.Listener
+	path string
+	send func(tea.Msg)
+}
+
+// ListenControl 在 path 上创建控制 socket，只有当前用户可以连接
+func ListenControl(path string) (*ControlServer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	// 同一 PID 留下的旧 socket 一定已经失效
+	_ = os.Remove(path)
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return &ControlServer{ln: ln, path: path}, nil
+}
+
+// Path socket 文件路径
+func (s *ControlServer) Path() string { return s.path }
+
+// Serve 把收到的命令注入 p，直到 Close
+func (s *ControlServer) Serve(p *tea.Program) {
+	s.send = p.Send
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+		go s.handle(conn)
+	}
+}
+
+// Close 停止监听并删除 socket 文件
+func (s *ControlServer) Close() error {
+	err := s.ln.Close()
+	_ = os.Remove(s.path)
+	return err
+}
+
+func (s *ControlServer) handle(conn net.Conn) {
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	enc := json.NewEncoder(conn)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if err := enc.Encode(s.exec(line)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *ControlServer) exec(line string) ControlReply {
+	r := newReporter()
+	s.send(controlRequest{line: line, reporter: r})
+	select {
+	case reply := <-r.reply:
+		return reply
+	case <-time.After(controlTimeout):
+		return ControlReply{OK: true, Message: "submitted (no result within " + controlTimeout.String() + ")"}
+	}
+}
+
+// handleControl 在 UI 线程里执行控制命令，效果与在命令模式中输入相同
+func (m *Model) handleControl(req controlRequest) tea.Cmd {
+	name, handler, args := pages.LookupCommand(req.line)
+	if handler == nil {
+		req.reporter.fail(fmt.Errorf("unknown command: %s", name))
+		return nil
+	}
+	view, cmd := handler(args, m.shared.As(core.SurfaceControl))
+	if view != nil {
+		req.reporter.done(ControlReply{OK: true, Message: "opened " + name})
+		return pages.Push(view)
+	}
+	cmd = req.reporter.track(cmd, true)
+	if cmd == nil {
+		req.reporter.done(ControlReply{OK: true, Message: "ok"})
+	}
+	return cmd
+}
+
+// handleControlForward 把命令产生的消息交给目标页面，并继续跟踪页面返回的 Cmd
+// 针对列表的消息直接送到栈底的 ListView，不受当前打开的页面影响
+func (m *Model) handleControlForward(msg controlForwardMsg) tea.Cmd {
+	idx := len(m.stack) - 1
+	switch msg.msg.(type) {
+	case pages.SetFilterMsg, pages.SelectMsg, pages.ClearSelectionMsg, pages.ExportMsg, pages.BatchSelectionMsg:
+		if _, ok := m.stack[0].(*pages.ListView); ok {
+			idx = 0
+		}
+	}
+
+	var cmd tea.Cmd
+	m.stack[idx], cmd = m.stack[idx].Update(msg.msg)
+	m.active = m.stack[len(m.stack)-1]
+
+	cmd = msg.reporter.track(cmd, false)
+	msg.reporter.release()
+	return cmd
+}
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/Microindole/quell/internal/config"
 	"github.com/Microindole/quell/internal/core"
@@ -16,28 +17,40 @@ import (
 var appStyle = lipgloss.NewStyle().Padding(1, 2)
 
 type Model struct {
+	cfg    *config.Config
 	shared *pages.SharedState
 	stack  []pages.View
 	active pages.View
+	size   tea.WindowSizeMsg // 最近一次的窗口尺寸，新页面入栈时补发
 }
 
 func NewModel(svc *core.Service, cfg *config.Config) *Model {
 	commands.RegisterAll(pages.CommandRegistry)
 	state := &pages.SharedState{
-		Service: svc,
+		Service: svc.As(core.SurfaceKey),
 		IsAdmin: system.IsAdmin(),
+		Batch:   core.BatchOptions(cfg.Batch),
 	}
 	initialView := pages.NewListView(state, cfg.SortIndex, cfg.TreeMode)
+	initialView.SetHeaderCollapsed(cfg.HeaderFold)
 	return &Model{
+		cfg:    cfg,
 		shared: state,
 		stack:  []pages.View{initialView},
 		active: initialView,
 	}
 }
 
+// SetReplay 进入回放模式：启用播放控制键并在状态栏显示回放进度
+func (m *Model) SetReplay(rc pages.ReplayControl) {
+	m.shared.Replay = rc
+}
+
 // GetSnapshot 收集当前应用状态用于保存
 func (m *Model) GetSnapshot() *config.Config {
-	cfg := &config.Config{}
+	// 以启动时的配置为基础，保留用户手动编辑的字段 (例如 cpu_mode)
+	snapshot := *m.cfg
+	cfg := &snapshot
 
 	// 1. 获取 Service 中的暂停列表 (返回的是匿名结构体切片)
 	rawList := m.shared.Service.GetPausedProcs()
@@ -58,6 +71,7 @@ func (m *Model) GetSnapshot() *config.Config {
 			sortIdx, treeMode := lv.GetState()
 			cfg.SortIndex = sortIdx
 			cfg.TreeMode = treeMode
+			cfg.HeaderFold = lv.HeaderCollapsed()
 		}
 	}
 
@@ -73,10 +87,13 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.size = msg
+
 	case pages.PushViewMsg:
 		m.stack = append(m.stack, msg.View)
 		m.active = msg.View
-		return m, msg.View.Init()
+		return m, tea.Batch(msg.View.Init(), m.resendSize())
 
 	case pages.PopViewMsg:
 		if len(m.stack) > 1 {
@@ -90,16 +107,36 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// 替换栈顶元素
 			m.stack[len(m.stack)-1] = msg.View
 			m.active = msg.View
-			return m, msg.View.Init()
+			return m, tea.Batch(msg.View.Init(), m.resendSize())
 		}
 
+	case controlRequest:
+		return m, m.handleControl(msg)
+
+	case controlForwardMsg:
+		return m, m.handleControlForward(msg)
+
+	case tea.MouseMsg:
+		// 鼠标坐标换算为相对页面左上角 (扣除 appStyle 的内边距)
+		msg.X -= appStyle.GetPaddingLeft()
+		msg.Y -= appStyle.GetPaddingTop()
+		var cmd tea.Cmd
+		m.active, cmd = m.active.Update(msg)
+		m.stack[len(m.stack)-1] = m.active
+		return m, cmd
+
 	case tea.KeyMsg:
 		if msg.String() == "ctrl+c" {
-			if _, ok := m.active.(*pages.ConfirmDialog); ok {
+			switch m.active.(type) {
+			case *pages.ConfirmDialog, *pages.ActionDialog:
 				return m, tea.Quit
 			}
 			return m, pages.Push(pages.NewConfirmDialog("Really quit Quell?", tea.Quit))
 		}
+		if m.shared.Replay != nil && m.handleReplayKey(msg.String()) {
+			// 跳转后立即刷新，不必等下一个心跳
+			return m, func() tea.Msg { return pages.ForceRefreshMsg{} }
+		}
 
 	case pages.TickMsg:
 		// 1. 续订下一个心跳 (保证循环不断)
@@ -120,6 +157,39 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// handleReplayKey 回放控制键，均带修饰键以免与页面内的输入冲突
+func (m *Model) handleReplayKey(k string) bool {
+	rc := m.shared.Replay
+	switch k {
+	case "ctrl+p":
+		rc.TogglePause()
+	case "ctrl+left":
+		rc.Seek(-10 * time.Second)
+	case "ctrl+right":
+		rc.Seek(10 * time.Second)
+	case "shift+left":
+		rc.Seek(-time.Minute)
+	case "shift+right":
+		rc.Seek(time.Minute)
+	case "ctrl+up":
+		rc.ScaleSpeed(2)
+	case "ctrl+down":
+		rc.ScaleSpeed(0.5)
+	default:
+		return false
+	}
+	return true
+}
+
+// resendSize 让新入栈的页面也能拿到窗口尺寸
+func (m *Model) resendSize() tea.Cmd {
+	if m.size.Width == 0 {
+		return nil
+	}
+	size := m.size
+	return func() tea.Msg { return size }
+}
+
 func (m *Model) View() string {
 	content := m.active.View()
 
@@ -136,9 +206,20 @@ func (m *Model) View() string {
 	// 如果是 DetailView，也可以显示特定状态
 	if _, ok := m.active.(*pages.DetailView); ok {
 		extraInfo = " | Inspecting..."
+	} else if sr, ok := m.active.(pages.StatusReporter); ok && extraInfo == "" {
+		extraInfo = " | " + sr.GetStatus()
 	}
 
 	statusText := authIcon + extraInfo
+	switch m.shared.Service.Mode() {
+	case core.ModeReadOnly:
+		statusText = "🔒 READ-ONLY | " + statusText
+	case core.ModeDryRun:
+		statusText = "🧪 DRY-RUN | " + statusText
+	}
+	if m.shared.Replay != nil {
+		statusText = m.shared.Replay.Status() + " | " + statusText
+	}
 	statusBar := components.RenderStatusBar(statusText)
 
 	return appStyle.Render(content + "\n" + statusBar)
@@ -0,0 +1,349 @@
+package pages
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Microindole/quell/internal/core"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	actionTitleStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#FAFAFA")).Bold(true)
+	actionHeadStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262")).Bold(true)
+	actionWarnStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFA500"))
+	actionRefuseStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87"))
+	buttonStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("#A0A0A0")).Background(lipgloss.Color("#3A3A3A")).Padding(0, 1)
+	buttonFocusStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#1A1A1A")).Background(borderColor).Bold(true).Padding(0, 1)
+	buttonDangerStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FAFAFA")).Background(lipgloss.Color("#D7005F")).Bold(true).Padding(0, 1)
+)
+
+const (
+	actionMaxRows      = 8 // 最多列出的目标进程数
+	actionButtonGap    = 2
+	actionDialogWidth  = 84
+	actionDialogMargin = 4
+)
+
+// Choice 确认框中的一个按钮
+type Choice struct {
+	Label  string
+	Key    string  // 快捷键，输入确认模式下不可用
+	Action tea.Cmd // 为 nil 时表示取消
+	Danger bool    // 获得焦点时以红色显示
+}
+
+// CancelChoice 每个确认框最后的 "取消" 按钮
+var CancelChoice = Choice{Label: "Cancel", Key: "n"}
+
+// buttonZone 按钮在页面中的位置，用于鼠标点击
+type buttonZone struct {
+	x0, x1, y int
+	idx       int
+}
+
+// ActionDialog 发送信号前的确认框：列出目标进程的子进程数、端口、用户和风险提示，
+// 提供多个按钮，可以用方向键、快捷键或鼠标选择
+// 有受保护的进程时需要先输入进程名 (批量时输入 yes)，此时快捷键不可用
+type ActionDialog struct {
+	title   string
+	impacts []core.Impact
+	skipped int // 会被保护机制拒绝的进程数
+	choices []Choice
+	focus   int
+
+	expect    string
+	input     textinput.Model
+	onConfirm func() // 输入确认通过后、执行操作之前调用
+	mismatch  bool
+
+	width  int
+	height int
+	zones  []buttonZone
+}
+
+func NewActionDialog(title string, impacts []core.Impact, choices []Choice) *ActionDialog {
+	d := &ActionDialog{title: title, impacts: impacts, choices: choices}
+	for _, im := range impacts {
+		if im.Verdict.Level == core.GuardRefuse {
+			d.skipped++
+		}
+	}
+	return d
+}
+
+// SetFocus 初始获得焦点的按钮，例如按 X 打开时默认选中 "Kill -9"
+func (d *ActionDialog) SetFocus(i int) *ActionDialog {
+	if i >= 0 && i < len(d.choices) {
+		d.focus = i
+	}
+	return d
+}
+
+// RequireTyped 需要输入 expect 才能执行，通过后先调用 onConfirm
+func (d *ActionDialog) RequireTyped(expect string, onConfirm func()) *ActionDialog {
+	ti := textinput.New()
+	ti.Placeholder = expect
+	ti.CharLimit = 64
+	ti.Width = 30
+	ti.Focus()
+	d.expect, d.input, d.onConfirm = expect, ti, onConfirm
+	return d
+}
+
+func (d *ActionDialog) Init() tea.Cmd {
+	// 鼠标只在确认框打开期间启用，不影响其他页面的文字选择
+	if d.expect != "" {
+		return tea.Batch(tea.EnableMouseCellMotion, textinput.Blink)
+	}
+	return tea.EnableMouseCellMotion
+}
+
+func (d *ActionDialog) Update(msg tea.Msg) (View, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// 扣除外层 appStyle 的内边距
+		d.width = msg.Width - 4
+		d.height = msg.Height - 2
+
+	case tea.MouseMsg:
+		if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+			return d, nil
+		}
+		for _, z := range d.zones {
+			if msg.Y == z.y && msg.X >= z.x0 && msg.X < z.x1 {
+				d.focus = z.idx
+				return d, d.choose(z.idx)
+			}
+		}
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return d, tea.Quit
+		case "esc":
+			return d, d.close()
+		case "tab":
+			d.move(1)
+			return d, nil
+		case "shift+tab":
+			d.move(-1)
+			return d, nil
+		case "enter":
+			return d, d.choose(d.focus)
+		}
+		if d.expect != "" {
+			var cmd tea.Cmd
+			d.input, cmd = d.input.Update(msg)
+			d.mismatch = false
+			return d, cmd
+		}
+		switch msg.String() {
+		case "left", "h":
+			d.move(-1)
+		case "right", "l":
+			d.move(1)
+		case "y":
+			return d, d.choose(d.focus)
+		case "q":
+			return d, d.close()
+		default:
+			for i, c := range d.choices {
+				if c.Key != "" && msg.String() == c.Key {
+					d.focus = i
+					return d, d.choose(i)
+				}
+			}
+		}
+	}
+	return d, nil
+}
+
+func (d *ActionDialog) move(delta int) {
+	n := len(d.choices)
+	d.focus = ((d.focus+delta)%n + n) % n
+}
+
+// close 取消：关闭确认框并恢复鼠标
+func (d *ActionDialog) close() tea.Cmd {
+	return tea.Batch(Pop(), tea.DisableMouse)
+}
+
+// choose 执行第 i 个按钮；输入确认模式下输入不一致时清空重来
+func (d *ActionDialog) choose(i int) tea.Cmd {
+	c := d.choices[i]
+	if c.Action == nil {
+		return d.close()
+	}
+	if d.expect != "" {
+		if d.input.Value() != d.expect {
+			d.input.SetValue("")
+			d.mismatch = true
+			return nil
+		}
+		if d.onConfirm != nil {
+			d.onConfirm()
+		}
+	}
+	// 按顺序执行：先回到上一页，操作结果 (或结果页) 才会交给正确的页面
+	return tea.Sequence(Pop(), tea.DisableMouse, c.Action)
+}
+
+func (d *ActionDialog) boxWidth() int {
+	w := actionDialogWidth
+	if d.width > 0 && d.width-actionDialogMargin < w {
+		w = d.width - actionDialogMargin
+	}
+	if w < 40 {
+		w = 40
+	}
+	return w
+}
+
+func (d *ActionDialog) View() string {
+	w := d.boxWidth()
+	inner := w - 2 // 左右各一格内边距
+
+	lines := []string{
+		headerStyle.Width(inner).Render("WARNING"),
+		"",
+		actionTitleStyle.Width(inner).Render(d.title),
+		"",
+	}
+	lines = append(lines, d.targetLines(inner)...)
+
+	if d.skipped > 0 {
+		lines = append(lines, "", actionRefuseStyle.Render(truncate(fmt.Sprintf("%d protected processes will be skipped", d.skipped), inner)))
+	}
+	if d.expect != "" {
+		prompt := "Type " + d.expect + " to confirm:"
+		if d.mismatch {
+			prompt = "Does not match, type " + d.expect + ":"
+		}
+		lines = append(lines, "", actionWarnStyle.Render(truncate(prompt, inner)), d.input.View())
+	}
+
+	// 按钮行：位置记下来供鼠标点击
+	lines = append(lines, "")
+	buttonRow := len(lines)
+	var buttons []string
+	widths := make([]int, len(d.choices))
+	for i, c := range d.choices {
+		label := c.Label
+		if c.Key != "" && d.expect == "" {
+			label = "[" + c.Key + "] " + label
+		}
+		style := buttonStyle
+		if i == d.focus {
+			style = buttonFocusStyle
+			if c.Danger {
+				style = buttonDangerStyle
+			}
+		}
+		b := style.Render(label)
+		widths[i] = lipgloss.Width(b)
+		buttons = append(buttons, b)
+	}
+	row := strings.Join(buttons, strings.Repeat(" ", actionButtonGap))
+	rowPad := (inner - lipgloss.Width(row)) / 2
+	if rowPad < 0 {
+		rowPad = 0
+	}
+	lines = append(lines, strings.Repeat(" ", rowPad)+row)
+
+	hint := "←/→ choose · enter confirm · esc cancel"
+	if d.expect != "" {
+		hint = "tab choose · enter confirm · esc cancel"
+	}
+	lines = append(lines, "", tabHintStyle.Render(hint))
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Padding(0, 1).
+		Width(w).
+		Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+
+	// 手动居中 (而不是 lipgloss.Place)，这样才能算出按钮在页面中的坐标
+	areaW, areaH := d.width, d.height-2
+	if areaW <= 0 {
+		areaW = 80
+	}
+	boxW, boxH := lipgloss.Width(box), lipgloss.Height(box)
+	left := max(0, (areaW-boxW)/2)
+	top := max(0, (areaH-boxH)/2)
+	if d.width <= 0 {
+		top = 2
+	}
+
+	// 按钮行在框内的行号：上边框 1 行 + 前面各行 (标题栏等可能折行，按实际高度累加)
+	y := top + 1
+	for _, l := range lines[:buttonRow] {
+		y += lipgloss.Height(l)
+	}
+	d.zones = d.zones[:0]
+	x := left + 2 + rowPad // 左边框与内边距
+	for i, bw := range widths {
+		d.zones = append(d.zones, buttonZone{x0: x, x1: x + bw, y: y, idx: i})
+		x += bw + actionButtonGap
+	}
+
+	// 补足高度，让状态栏保持在底部
+	var b strings.Builder
+	b.WriteString(strings.Repeat("\n", top))
+	pad := strings.Repeat(" ", left)
+	for i, l := range strings.Split(box, "\n") {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(pad + l)
+	}
+	if rest := areaH - top - boxH; rest > 0 {
+		b.WriteString(strings.Repeat("\n", rest))
+	}
+	return b.String()
+}
+
+// targetLines 目标进程表格：PID、名称、用户、子进程数、监听端口，下面一行是风险提示
+func (d *ActionDialog) targetLines(inner int) []string {
+	nameW := inner - 7 - 1 - 10 - 1 - 8 - 1 - 14
+	if nameW < 8 {
+		nameW = 8
+	}
+	rowFmt := fmt.Sprintf("%%-7s %%-%ds %%-10s %%8s %%s", nameW)
+	lines := []string{actionHeadStyle.Render(truncate(fmt.Sprintf(rowFmt, "PID", "NAME", "USER", "CHILDREN", "PORTS"), inner))}
+
+	for i, im := range d.impacts {
+		if i >= actionMaxRows {
+			lines = append(lines, tabHintStyle.Render(fmt.Sprintf("... and %d more", len(d.impacts)-actionMaxRows)))
+			break
+		}
+		p := im.Process
+		var ports []string
+		for _, port := range p.Ports {
+			ports = append(ports, ":"+strconv.Itoa(port))
+		}
+		children := "-"
+		if im.Descendants > 0 {
+			children = strconv.Itoa(im.Descendants)
+		}
+		lines = append(lines, truncate(fmt.Sprintf(rowFmt, strconv.Itoa(int(p.PID)), truncate(p.Name, nameW), truncate(p.User, 10), children, strings.Join(ports, " ")), inner))
+
+		switch im.Verdict.Level {
+		case core.GuardRefuse:
+			lines = append(lines, actionRefuseStyle.Render(truncate("        refused: "+im.Verdict.Reason(), inner)))
+		case core.GuardConfirm:
+			lines = append(lines, actionRefuseStyle.Render(truncate("        protected: "+im.Verdict.Reason(), inner)))
+		}
+		if len(im.Warnings) > 0 {
+			lines = append(lines, actionWarnStyle.Render(truncate("        note: "+strings.Join(im.Warnings, "; "), inner)))
+		}
+	}
+	return lines
+}
+
+func (d *ActionDialog) ShortHelp() []key.Binding { return nil }
@@ -0,0 +1,296 @@
+package pages
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/Microindole/quell/internal/core"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	cpuOnStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("#1A1A1A")).Background(borderColor)
+	cpuOffStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#A0A0A0")).Background(lipgloss.Color("#3A3A3A"))
+	cpuOutsideMark = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87")).Bold(true)
+)
+
+const (
+	affinityDialogWidth = 72
+	cpuCellWidth        = 6 // "[ 12 ]" 或 "  12  "
+)
+
+// AffinityDialog CPU 亲和性编辑器：每个核一个格子，方向键移动、空格切换
+// 对照进程所在 cgroup 的 cpuset，提示包含了用不上的核或排除了允许的核
+// 初始值取第一个目标进程的当前亲和性
+type AffinityDialog struct {
+	state   *SharedState
+	targets []core.Process
+
+	info    core.AffinityInfo
+	err     error
+	cpus    []int // 格子对应的 CPU 编号
+	mask    map[int]bool
+	touched bool
+	cursor  int
+
+	threads     bool
+	subtree     bool
+	descendants int
+
+	width, height int
+}
+
+func NewAffinityDialog(state *SharedState, targets []core.Process) *AffinityDialog {
+	d := &AffinityDialog{state: state, targets: targets, mask: make(map[int]bool)}
+	d.info, d.err = state.Service.GetAffinity(targets[0].PID)
+	for _, c := range d.info.Mask {
+		d.mask[c] = true
+	}
+
+	// 格子：在线的 CPU；读不到时按 CPU 数量，再补上 mask 与 cpuset 中出现的编号
+	all := append(core.CPUSet{}, d.info.Online...)
+	if all == nil {
+		for c := 0; c < runtime.NumCPU(); c++ {
+			all = append(all, c)
+		}
+	}
+	all = append(all, d.info.Mask...)
+	all = append(all, d.info.Cpuset...)
+	d.cpus = core.NewCPUSet(all...)
+
+	d.descendants = len(WithDescendants(state.Service, targets)) - len(targets)
+	return d
+}
+
+func (d *AffinityDialog) Init() tea.Cmd { return nil }
+
+func (d *AffinityDialog) Update(msg tea.Msg) (View, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// 扣除外层 appStyle 的内边距
+		d.width = msg.Width - 4
+		d.height = msg.Height - 2
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return d, tea.Quit
+		case "esc", "q":
+			return d, Pop()
+		}
+		if d.err != nil {
+			return d, nil
+		}
+		cols := d.columns()
+		switch msg.String() {
+		case "left", "h":
+			d.move(-1)
+		case "right", "l":
+			d.move(1)
+		case "up", "k":
+			d.move(-cols)
+		case "down", "j":
+			d.move(cols)
+		case " ", "x":
+			cpu := d.cpus[d.cursor]
+			d.mask[cpu] = !d.mask[cpu]
+			d.touched = true
+		case "a":
+			d.setMask(d.cpus)
+		case "c":
+			// 恰好是 cgroup cpuset 允许的核
+			if d.info.Cpuset != nil {
+				d.setMask(d.info.Cpuset)
+			}
+		case "i":
+			for _, c := range d.cpus {
+				d.mask[c] = !d.mask[c]
+			}
+			d.touched = true
+		case "T":
+			d.threads = !d.threads
+		case "t":
+			d.subtree = !d.subtree
+		case "enter":
+			return d, d.apply()
+		}
+	}
+	return d, nil
+}
+
+func (d *AffinityDialog) move(delta int) {
+	d.cursor = max(0, min(len(d.cpus)-1, d.cursor+delta))
+}
+
+func (d *AffinityDialog) setMask(cpus core.CPUSet) {
+	d.mask = make(map[int]bool, len(cpus))
+	for _, c := range cpus {
+		d.mask[c] = true
+	}
+	d.touched = true
+}
+
+// current 当前勾选的 CPU
+func (d *AffinityDialog) current() core.CPUSet {
+	var cpus []int
+	for c, on := range d.mask {
+		if on {
+			cpus = append(cpus, c)
+		}
+	}
+	return core.NewCPUSet(cpus...)
+}
+
+// changed 单个目标时与原值比较；多个目标的当前值可能各不相同，改动过就按格子设置
+func (d *AffinityDialog) changed() bool {
+	if len(d.targets) > 1 || d.subtree {
+		return d.touched || d.threads
+	}
+	return !d.current().Equal(d.info.Mask) || d.threads
+}
+
+func (d *AffinityDialog) apply() tea.Cmd {
+	cpus := d.current()
+	if len(cpus) == 0 || !d.changed() {
+		return nil
+	}
+	op := OpAffinity(cpus, d.threads)
+	targets := d.targets
+	if d.subtree {
+		targets = WithDescendants(d.state.Service, targets)
+	}
+	// 先回到上一页，结果 (或批量结果页) 才会交给正确的页面
+	if len(targets) == 1 {
+		svc, pid := d.state.Service, targets[0].PID
+		return tea.Sequence(Pop(), func() tea.Msg { return op.Run(svc, pid) })
+	}
+	return tea.Sequence(Pop(), StartBatch(d.state, op, targets))
+}
+
+func (d *AffinityDialog) boxWidth() int {
+	w := affinityDialogWidth
+	if d.width > 0 && d.width-actionDialogMargin < w {
+		w = max(40, d.width-actionDialogMargin)
+	}
+	return w
+}
+
+// columns 每行的格子数：8 的倍数，放不下时减少
+func (d *AffinityDialog) columns() int {
+	cols := (d.boxWidth() - 2) / cpuCellWidth
+	if cols >= 8 {
+		cols -= cols % 8
+	}
+	return max(1, cols)
+}
+
+func (d *AffinityDialog) View() string {
+	w := d.boxWidth()
+	inner := w - 2
+
+	title := fmt.Sprintf("CPU affinity of %s (PID %d)", d.targets[0].Name, d.targets[0].PID)
+	if len(d.targets) > 1 {
+		title = fmt.Sprintf("CPU affinity of %d selected processes", len(d.targets))
+	}
+	lines := []string{
+		headerStyle.Width(inner).Render("AFFINITY"),
+		"",
+		actionTitleStyle.Width(inner).Render(truncate(title, inner)),
+		"",
+	}
+
+	if d.err != nil {
+		lines = append(lines, tabErrorStyle.Render(truncate("Cannot read affinity: "+d.err.Error(), inner)), "", tabHintStyle.Render("esc close"))
+		return d.place(w, lines)
+	}
+
+	lines = append(lines, d.grid()...)
+	lines = append(lines, "")
+
+	cpus := d.current()
+	maskLine := fmt.Sprintf("mask: %s (%d of %d CPUs)", cpus, len(cpus), len(d.cpus))
+	if len(cpus) == 0 {
+		maskLine = "mask: empty, select at least one CPU"
+	}
+	cpuset := "unknown"
+	if d.info.Cpuset != nil {
+		cpuset = d.info.Cpuset.String()
+	}
+	lines = append(lines,
+		truncate(maskLine, inner),
+		tabHintStyle.Render(truncate("was: "+d.info.Mask.String()+" · cgroup cpuset: "+cpuset, inner)),
+	)
+	if len(cpus) > 0 {
+		for _, warn := range d.info.Warnings(cpus) {
+			lines = append(lines, actionWarnStyle.Render(truncate("note: "+warn, inner)))
+		}
+	}
+
+	threads, tree := "off", fmt.Sprintf("off (%d descendants)", d.descendants)
+	if d.threads {
+		threads = "on"
+	}
+	if d.subtree {
+		tree = fmt.Sprintf("on (+%d descendants)", d.descendants)
+	}
+	lines = append(lines, "", truncate("[T] all threads: "+threads+" · [t] include children: "+tree, inner))
+
+	if len(cpus) > 0 && d.changed() {
+		n := len(d.targets)
+		if d.subtree {
+			n += d.descendants
+		}
+		lines = append(lines, actionTitleStyle.Render(truncate(fmt.Sprintf("enter: %s on %d processes", OpAffinity(cpus, d.threads).Label, n), inner)))
+	} else {
+		lines = append(lines, tabHintStyle.Render("no change"))
+	}
+	lines = append(lines, "", tabHintStyle.Render(truncate("←→↑↓ move · space toggle · a all · c cpuset · i invert · enter · esc", inner)))
+	return d.place(w, lines)
+}
+
+// grid 每个核一个格子：勾选的高亮，cgroup cpuset 之外的编号后面标 "!"，光标所在的格子加方括号
+func (d *AffinityDialog) grid() []string {
+	cols := d.columns()
+	var rows []string
+	for start := 0; start < len(d.cpus); start += cols {
+		var b strings.Builder
+		for i := start; i < min(start+cols, len(d.cpus)); i++ {
+			cpu := d.cpus[i]
+			style := cpuOffStyle
+			if d.mask[cpu] {
+				style = cpuOnStyle
+			}
+			open, close := " ", " "
+			if i == d.cursor {
+				open, close = "[", "]"
+			}
+			mark := " "
+			if d.info.Cpuset != nil && !d.info.Cpuset.Contains(cpu) {
+				mark = cpuOutsideMark.Render("!")
+			}
+			b.WriteString(open + style.Render(fmt.Sprintf("%3d", cpu)) + mark + close)
+		}
+		rows = append(rows, b.String())
+	}
+	return rows
+}
+
+func (d *AffinityDialog) place(w int, lines []string) string {
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Padding(0, 1).
+		Width(w).
+		Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+
+	if d.width > 0 && d.height > 0 {
+		// 预留底部状态栏的高度
+		return lipgloss.Place(d.width, max(0, d.height-2), lipgloss.Center, lipgloss.Center, box)
+	}
+	return "\n\n" + lipgloss.PlaceHorizontal(80, lipgloss.Center, box)
+}
+
+func (d *AffinityDialog) ShortHelp() []key.Binding { return nil }
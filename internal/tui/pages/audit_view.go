@@ -0,0 +1,178 @@
+package pages
+
+import (
+	"fmt"
+
+	"github.com/Microindole/quell/internal/core"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// auditItem 适配 list.Item
+type auditItem struct {
+	core.AuditEntry
+}
+
+func (i auditItem) Title() string {
+	mark := "✔"
+	if i.Failed() {
+		mark = "✘"
+	} else if i.Result == core.AuditDryRun {
+		mark = "~"
+	}
+	what := i.Action
+	if i.Signal != "" {
+		what += " " + i.Signal
+	}
+	if i.Detail != "" {
+		what += " " + i.Detail
+	}
+	return fmt.Sprintf("%s %s  %-16s %s (PID %d)", mark, i.Time.Local().Format("01-02 15:04:05"), what, i.Name, i.PID)
+}
+
+func (i auditItem) Description() string {
+	user := i.User
+	if i.SudoUser != "" {
+		user += " (sudo " + i.SudoUser + ")"
+	}
+	desc := fmt.Sprintf("%s via %s | %s", user, i.Surface, i.Result)
+	if i.Error != "" {
+		desc += ": " + i.Error
+	}
+	return desc
+}
+
+func (i auditItem) FilterValue() string {
+	return fmt.Sprintf("%s %d %s %s %s %s", i.Name, i.PID, i.Action, i.Signal, i.Surface, i.Result)
+}
+
+type auditEntriesMsg struct {
+	entries []core.AuditEntry
+	err     error
+}
+
+// AuditView 浏览审计日志，最新的操作在最上面
+type AuditView struct {
+	state    *SharedState
+	registry *HandlerRegistry
+	list     list.Model
+	filter   core.AuditFilter
+	loading  bool
+	status   string
+	entries  []core.AuditEntry
+}
+
+func NewAuditView(state *SharedState, filter core.AuditFilter) *AuditView {
+	d := list.NewDefaultDelegate()
+	d.SetSpacing(0)
+
+	l := list.New([]list.Item{}, d, 0, 0)
+	l.Title = "Audit Log"
+	l.SetShowHelp(false)
+	l.SetStatusBarItemName("entry", "entries")
+
+	v := &AuditView{
+		state:    state,
+		registry: &HandlerRegistry{},
+		list:     l,
+		filter:   filter,
+		loading:  true,
+		status:   "Reading audit log...",
+	}
+	v.registerActions()
+	return v
+}
+
+func (v *AuditView) Init() tea.Cmd {
+	return v.loadCmd()
+}
+
+func (v *AuditView) Update(msg tea.Msg) (View, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		v.list.SetSize(msg.Width-4, msg.Height-4)
+		return v, nil
+
+	case auditEntriesMsg:
+		v.loading = false
+		if msg.err != nil {
+			v.status = fmt.Sprintf("Error: %v", msg.err)
+			return v, nil
+		}
+		v.entries = msg.entries
+		items := make([]list.Item, len(msg.entries))
+		failed := 0
+		for i, e := range msg.entries {
+			items[i] = auditItem{e}
+			if e.Failed() {
+				failed++
+			}
+		}
+		v.status = fmt.Sprintf("%d entries (%d failed) | %s", len(msg.entries), failed, v.state.Service.AuditLogPath())
+		return v, v.list.SetItems(items)
+
+	case tea.KeyMsg:
+		if v.list.FilterState() != list.Filtering {
+			if cmd, handled := v.registry.Handle(msg, v); handled {
+				return v, cmd
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	v.list, cmd = v.list.Update(msg)
+	return v, cmd
+}
+
+func (v *AuditView) registerActions() {
+	v.registry.Register(key.NewBinding(key.WithKeys("esc", "q"), key.WithHelp("esc", "back")),
+		func(m View) (tea.Cmd, bool) {
+			if v.list.FilterState() == list.FilterApplied {
+				v.list.ResetFilter()
+				return nil, true
+			}
+			return Pop(), true
+		})
+
+	v.registry.Register(key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "reload")),
+		func(m View) (tea.Cmd, bool) {
+			v.loading = true
+			v.status = "Reading audit log..."
+			return v.loadCmd(), true
+		})
+
+	// 只看没有生效的操作 (失败、被拒绝、只读)
+	v.registry.Register(key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "failed only")),
+		func(m View) (tea.Cmd, bool) {
+			v.filter.Failed = !v.filter.Failed
+			return v.loadCmd(), true
+		})
+}
+
+func (v *AuditView) loadCmd() tea.Cmd {
+	path := v.state.Service.AuditLogPath()
+	filter := v.filter
+	return func() tea.Msg {
+		if path == "" {
+			return auditEntriesMsg{err: fmt.Errorf("audit log is disabled")}
+		}
+		entries, err := core.ReadAuditLog(path, filter)
+		return auditEntriesMsg{entries: entries, err: err}
+	}
+}
+
+func (v *AuditView) View() string {
+	if v.loading && len(v.entries) == 0 {
+		return "\n" + loadingTextStyle.Render(v.status)
+	}
+	if len(v.entries) == 0 {
+		return "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("#04B575")).Render("✔ No matching actions recorded.")
+	}
+	return v.list.View()
+}
+
+func (v *AuditView) ShortHelp() []key.Binding { return v.registry.MakeHelp() }
+
+func (v *AuditView) GetStatus() string { return v.status }
@@ -0,0 +1,287 @@
+package pages
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Microindole/quell/internal/core"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// BatchDoneMsg 离开批量结果页时发给上一页：列表据此取消勾选成功的进程并显示汇总
+type BatchDoneMsg struct {
+	Op      Operation
+	Results []core.BatchResult
+}
+
+// Summary 汇总成一条状态栏消息；全部是演练时以 ErrDryRun 返回
+func (m BatchDoneMsg) Summary() ProcessActionMsg {
+	ok, dry := 0, 0
+	var failed []string
+	for _, r := range m.Results {
+		switch {
+		case r.Err == nil:
+			ok++
+		case errors.Is(r.Err, core.ErrDryRun):
+			dry++
+		default:
+			failed = append(failed, fmt.Sprintf("%d (%s)", r.Process.PID, r.Process.Name))
+		}
+	}
+	if dry > 0 && ok == 0 && len(failed) == 0 {
+		verb := m.Op.verb()
+		return ProcessActionMsg{Err: fmt.Errorf("%w: would %s %d processes", core.ErrDryRun, strings.ToLower(verb[:1])+verb[1:], dry)}
+	}
+	summary := fmt.Sprintf("%s: %d/%d processes", m.Op.Past, ok, len(m.Results))
+	if len(failed) > 0 {
+		return ProcessActionMsg{Err: fmt.Errorf("%s, failed: %s", summary, strings.Join(failed, ", "))}
+	}
+	return ProcessActionMsg{Action: summary}
+}
+
+// StartBatch 在后台对 procs 批量执行 op，完成后打开结果页
+// 并发数与是否先处理子进程取自 SharedState.Batch
+func StartBatch(state *SharedState, op Operation, procs []core.Process) tea.Cmd {
+	return func() tea.Msg {
+		results := state.Service.RunBatch(procs, state.Batch, func(pid int32) error { return op.Apply(state.Service, pid) })
+		return PushViewMsg{View: NewBatchView(state, op, results)}
+	}
+}
+
+// ConfirmBatch 批量执行 op 的确认流程：需要保护检查的操作先弹出确认框，恢复运行直接执行
+func ConfirmBatch(state *SharedState, op Operation, procs []core.Process) tea.Cmd {
+	run := StartBatch(state, op, procs)
+	if !op.Guarded {
+		return run
+	}
+	return ConfirmAction(state, op.Prompt(len(procs)), procs, 0, op.Choice("y", run), CancelChoice)
+}
+
+// WithDescendants 在 procs 后面追加它们的全部子孙进程 (按最近一次扫描)，重复的只保留一个
+func WithDescendants(svc *core.Service, procs []core.Process) []core.Process {
+	seen := make(map[int32]bool, len(procs))
+	out := make([]core.Process, 0, len(procs))
+	for _, p := range procs {
+		if !seen[p.PID] {
+			seen[p.PID] = true
+			out = append(out, p)
+		}
+	}
+	for _, p := range procs {
+		for _, d := range svc.Descendants(p.PID) {
+			if !seen[d.PID] {
+				seen[d.PID] = true
+				out = append(out, d)
+			}
+		}
+	}
+	return out
+}
+
+// batchItem 适配 list.Item
+type batchItem struct {
+	core.BatchResult
+	op Operation
+}
+
+func (i batchItem) Title() string {
+	mark := "✔"
+	if i.Failed() {
+		mark = "✘"
+	} else if i.Err != nil {
+		mark = "~"
+	}
+	return fmt.Sprintf("%s %s (PID %d, %s)", mark, i.Process.Name, i.Process.PID, i.Process.User)
+}
+
+func (i batchItem) Description() string {
+	if i.Err == nil {
+		return i.op.Past
+	}
+	if errors.Is(i.Err, core.ErrDryRun) {
+		return i.Err.Error()
+	}
+	return i.Status() + ": " + i.Err.Error()
+}
+
+func (i batchItem) FilterValue() string {
+	return fmt.Sprintf("%s %d %s", i.Process.Name, i.Process.PID, i.Status())
+}
+
+// procIdentity PID 加启动时间，避免 PID 复用后认错进程
+type procIdentity struct {
+	pid        int32
+	createTime int64
+}
+
+func identityOf(p core.Process) procIdentity { return procIdentity{p.PID, p.CreateTime} }
+
+type batchRetryMsg struct {
+	results []core.BatchResult
+}
+
+// BatchView 批量操作的结果页：每个目标一行，显示成功或失败的原因，可以只重试失败的目标
+type BatchView struct {
+	state    *SharedState
+	registry *HandlerRegistry
+	list     list.Model
+	op       Operation
+	results  []core.BatchResult
+	attempt  int
+	running  bool
+	status   string
+}
+
+func NewBatchView(state *SharedState, op Operation, results []core.BatchResult) *BatchView {
+	d := list.NewDefaultDelegate()
+	d.SetSpacing(0)
+
+	l := list.New([]list.Item{}, d, 0, 0)
+	l.Title = op.Label + " (batch)"
+	l.SetShowHelp(false)
+	l.SetStatusBarItemName("target", "targets")
+
+	v := &BatchView{
+		state:    state,
+		registry: &HandlerRegistry{},
+		list:     l,
+		op:       op,
+		results:  results,
+		attempt:  1,
+	}
+	v.registerActions()
+	v.refresh()
+	return v
+}
+
+// Summary 当前结果的汇总，控制 socket 用它作为命令的应答
+func (v *BatchView) Summary() ProcessActionMsg {
+	return BatchDoneMsg{Op: v.op, Results: v.results}.Summary()
+}
+
+func (v *BatchView) Init() tea.Cmd { return nil }
+
+func (v *BatchView) Update(msg tea.Msg) (View, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		v.list.SetSize(msg.Width-4, msg.Height-4)
+		return v, nil
+
+	case batchRetryMsg:
+		// 按进程身份把重试结果合并回原来的位置
+		retried := make(map[procIdentity]core.BatchResult, len(msg.results))
+		for _, r := range msg.results {
+			retried[identityOf(r.Process)] = r
+		}
+		for i, r := range v.results {
+			if nr, ok := retried[identityOf(r.Process)]; ok {
+				v.results[i] = nr
+			}
+		}
+		v.attempt++
+		v.running = false
+		v.refresh()
+		return v, nil
+
+	case ProcessActionMsg:
+		// 重试的确认被拒绝 (只读模式、受保护) 时的提示
+		if msg.Err != nil {
+			v.status = errorStatus(msg.Err)
+		}
+		return v, nil
+
+	case tea.KeyMsg:
+		if v.list.FilterState() != list.Filtering {
+			if cmd, handled := v.registry.Handle(msg, v); handled {
+				return v, cmd
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	v.list, cmd = v.list.Update(msg)
+	return v, cmd
+}
+
+func (v *BatchView) registerActions() {
+	v.registry.Register(key.NewBinding(key.WithKeys("esc", "q", "enter"), key.WithHelp("esc", "back")),
+		func(m View) (tea.Cmd, bool) {
+			if v.list.FilterState() == list.FilterApplied {
+				v.list.ResetFilter()
+				return nil, true
+			}
+			done := BatchDoneMsg{Op: v.op, Results: v.results}
+			return tea.Sequence(Pop(), func() tea.Msg { return done }), true
+		})
+
+	// 只重试失败的目标；需要保护检查的操作会重新确认
+	v.registry.Register(key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "retry failed")),
+		func(m View) (tea.Cmd, bool) {
+			if v.running {
+				return nil, true
+			}
+			failed := v.failed()
+			if len(failed) == 0 {
+				v.status = "Nothing to retry."
+				return nil, true
+			}
+			if !v.op.Guarded {
+				v.running = true
+				v.status = fmt.Sprintf("Retrying %d processes...", len(failed))
+				return v.retryCmd(failed), true
+			}
+			return ConfirmAction(v.state, "Retry: "+v.op.Prompt(len(failed)), failed, 0, v.op.Choice("y", v.retryCmd(failed)), CancelChoice), true
+		})
+}
+
+func (v *BatchView) retryCmd(procs []core.Process) tea.Cmd {
+	state, op := v.state, v.op
+	return func() tea.Msg {
+		results := state.Service.RunBatch(procs, state.Batch, func(pid int32) error { return op.Apply(state.Service, pid) })
+		return batchRetryMsg{results: results}
+	}
+}
+
+func (v *BatchView) failed() []core.Process {
+	var procs []core.Process
+	for _, r := range v.results {
+		if r.Failed() {
+			procs = append(procs, r.Process)
+		}
+	}
+	return procs
+}
+
+func (v *BatchView) refresh() {
+	items := make([]list.Item, len(v.results))
+	for i, r := range v.results {
+		items[i] = batchItem{BatchResult: r, op: v.op}
+	}
+	v.list.SetItems(items)
+
+	failed := len(v.failed())
+	v.status = fmt.Sprintf("%d/%d ok, %d failed", len(v.results)-failed, len(v.results), failed)
+	if v.attempt > 1 {
+		v.status += fmt.Sprintf(" | attempt %d", v.attempt)
+	}
+	if v.state.Batch.ChildrenFirst {
+		v.status += " | children first"
+	}
+	if failed > 0 {
+		v.status += " | r: retry failed"
+	}
+}
+
+func (v *BatchView) View() string {
+	if len(v.results) == 0 {
+		return "\n" + loadingTextStyle.Render("No targets.")
+	}
+	return v.list.View()
+}
+
+func (v *BatchView) ShortHelp() []key.Binding { return v.registry.MakeHelp() }
+
+func (v *BatchView) GetStatus() string { return v.status }
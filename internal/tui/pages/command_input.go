@@ -4,6 +4,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/Microindole/quell/internal/core"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -133,19 +134,14 @@ func (c *CommandInput) executeCommand(cmdStr string) (View, tea.Cmd) {
 		return c, nil
 	}
 
-	parts := strings.Fields(cmdStr)
-	cmdName := parts[0]
-	var args []string
-	if len(parts) > 1 {
-		args = parts[1:]
-	}
-
-	if handler, exists := CommandRegistry[cmdName]; exists {
-		view, cmd := handler(args, c.state)
+	cmdName, handler, args := LookupCommand(cmdStr)
+	if handler != nil {
+		view, cmd := handler(args, c.state.As(core.SurfaceCommand))
 		if view != nil {
 			return c, Replace(view)
 		}
-		return c, cmd
+		// 先关闭输入框，保证命令产生的消息送达下面的页面
+		return c, tea.Sequence(Pop(), cmd)
 	}
 
 	c.textInput.SetValue("")
@@ -55,8 +55,9 @@ func (c *ConfirmDialog) Init() tea.Cmd { return nil }
 func (c *ConfirmDialog) Update(msg tea.Msg) (View, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		c.width = msg.Width
-		c.height = msg.Height
+		// 扣除外层 appStyle 的内边距
+		c.width = msg.Width - 4
+		c.height = msg.Height - 2
 
 	case tea.KeyMsg:
 		switch msg.String() {
@@ -0,0 +1,199 @@
+package pages
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/Microindole/quell/internal/core"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// deletedFileItem 适配 list.Item
+type deletedFileItem struct {
+	core.DeletedFile
+}
+
+func (i deletedFileItem) Title() string {
+	return fmt.Sprintf("%-10s %s (PID %d) · holds %s in total", core.FormatBytes(uint64(i.File.Size)),
+		i.Holder.Name, i.Holder.PID, core.FormatBytes(i.HolderTotal))
+}
+
+func (i deletedFileItem) Description() string {
+	fds := make([]string, len(i.Fds))
+	for n, fd := range i.Fds {
+		fds[n] = strconv.Itoa(fd)
+	}
+	desc := fmt.Sprintf("fd %s → %s (deleted)", strings.Join(fds, ", "), i.File.Path)
+	if i.Holders > 1 {
+		desc += fmt.Sprintf(" · also held by %d other processes", i.Holders-1)
+	}
+	return desc
+}
+
+func (i deletedFileItem) FilterValue() string {
+	return fmt.Sprintf("%s %d %s", i.Holder.Name, i.Holder.PID, i.File.Path)
+}
+
+type deletedFilesMsg struct {
+	files []core.DeletedFile
+	err   error
+}
+
+// DeletedFilesView 系统级报告：列出“已删除但仍被打开”的文件
+// 磁盘被占满却找不到大文件时，通常就是这些 fd 在作怪
+type DeletedFilesView struct {
+	state    *SharedState
+	registry *HandlerRegistry
+	list     list.Model
+	loading  bool
+	status   string
+	files    []core.DeletedFile
+}
+
+func NewDeletedFilesView(state *SharedState) *DeletedFilesView {
+	d := list.NewDefaultDelegate()
+	d.SetSpacing(0)
+
+	l := list.New([]list.Item{}, d, 0, 0)
+	l.Title = "Deleted But Open Files"
+	l.SetShowHelp(false)
+	l.SetStatusBarItemName("file", "files")
+
+	v := &DeletedFilesView{
+		state:    state,
+		registry: &HandlerRegistry{},
+		list:     l,
+		loading:  true,
+		status:   "Scanning open files...",
+	}
+	v.registerActions()
+	return v
+}
+
+func (v *DeletedFilesView) Init() tea.Cmd {
+	return v.scanCmd()
+}
+
+func (v *DeletedFilesView) Update(msg tea.Msg) (View, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		v.list.SetSize(msg.Width-4, msg.Height-4)
+		return v, nil
+
+	case deletedFilesMsg:
+		v.loading = false
+		if msg.err != nil {
+			v.status = fmt.Sprintf("Error: %v", msg.err)
+			return v, nil
+		}
+		v.files = msg.files
+		items := make([]list.Item, len(msg.files))
+		for i, f := range msg.files {
+			items[i] = deletedFileItem{f}
+		}
+		v.status = fmt.Sprintf("%d deleted files | %s reclaimable",
+			len(msg.files), core.FormatBytes(core.ReclaimableBytes(msg.files)))
+		return v, v.list.SetItems(items)
+
+	case ProcessActionMsg:
+		if msg.Err != nil {
+			v.status = errorStatus(msg.Err)
+			return v, nil
+		}
+		v.status = fmt.Sprintf("%s successfully. Rescanning...", msg.Action)
+		return v, v.scanCmd()
+
+	case tea.KeyMsg:
+		if v.list.FilterState() != list.Filtering {
+			if cmd, handled := v.registry.Handle(msg, v); handled {
+				return v, cmd
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	v.list, cmd = v.list.Update(msg)
+	return v, cmd
+}
+
+func (v *DeletedFilesView) registerActions() {
+	v.registry.Register(key.NewBinding(key.WithKeys("esc", "q"), key.WithHelp("esc", "back")),
+		func(m View) (tea.Cmd, bool) {
+			if v.list.FilterState() == list.FilterApplied {
+				v.list.ResetFilter()
+				return nil, true
+			}
+			return Pop(), true
+		})
+
+	v.registry.Register(key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "rescan")),
+		func(m View) (tea.Cmd, bool) {
+			v.loading = true
+			v.status = "Scanning open files..."
+			return v.scanCmd(), true
+		})
+
+	// 杀掉持有者，释放空间
+	v.registry.Register(key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "kill holder")),
+		func(m View) (tea.Cmd, bool) {
+			item, ok := v.list.SelectedItem().(deletedFileItem)
+			if !ok {
+				return nil, false
+			}
+			pid := item.Holder.PID
+			run := func(op Operation) tea.Cmd {
+				return func() tea.Msg { return op.Run(v.state.Service, pid) }
+			}
+			return ConfirmAction(v.state,
+				fmt.Sprintf("Kill %s (PID %d) to release up to %s?", item.Holder.Name, pid, core.FormatBytes(item.HolderTotal)),
+				[]core.Process{item.Holder}, 0,
+				OpTerminate.Choice("t", run(OpTerminate)),
+				OpForceKill.Choice("k", run(OpForceKill)),
+				CancelChoice,
+			), true
+		})
+
+	// 发送 SIGHUP：大多数守护进程 (nginx, rsyslog...) 收到后会重新打开日志文件
+	v.registry.Register(key.NewBinding(key.WithKeys("R"), key.WithHelp("R", "restart (SIGHUP)")),
+		func(m View) (tea.Cmd, bool) {
+			item, ok := v.list.SelectedItem().(deletedFileItem)
+			if !ok {
+				return nil, false
+			}
+			pid := item.Holder.PID
+			op := OpSignal(syscall.SIGHUP)
+			op.Danger = false
+			return ConfirmAction(v.state,
+				fmt.Sprintf("Send SIGHUP to %s (PID %d) so it reopens its files?", item.Holder.Name, pid),
+				[]core.Process{item.Holder}, 0,
+				op.Choice("y", func() tea.Msg { return op.Run(v.state.Service, pid) }),
+				CancelChoice,
+			), true
+		})
+}
+
+func (v *DeletedFilesView) scanCmd() tea.Cmd {
+	return func() tea.Msg {
+		files, err := v.state.Service.FindDeletedFiles()
+		return deletedFilesMsg{files: files, err: err}
+	}
+}
+
+func (v *DeletedFilesView) View() string {
+	if v.loading && len(v.files) == 0 {
+		return "\n" + loadingTextStyle.Render(v.status)
+	}
+	if len(v.files) == 0 {
+		return "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("#04B575")).Render("✔ No deleted files are being held open.")
+	}
+	return v.list.View()
+}
+
+func (v *DeletedFilesView) ShortHelp() []key.Binding { return v.registry.MakeHelp() }
+
+func (v *DeletedFilesView) GetStatus() string { return v.status }
@@ -0,0 +1,384 @@
+package pages
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Microindole/quell/internal/core"
+	"github.com/Microindole/quell/internal/tui/components"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	tabHintStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262")).Italic(true)
+	tabErrorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFA500")).Bold(true)
+	envKeyStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#04B575"))
+
+	catalogPolicyStyles = map[core.CatalogPolicy]lipgloss.Style{
+		core.PolicyNone:  lipgloss.NewStyle(),
+		core.PolicySafe:  lipgloss.NewStyle().Foreground(lipgloss.Color("#04B575")).Bold(true),
+		core.PolicyWarn:  lipgloss.NewStyle().Foreground(lipgloss.Color("#FFA500")).Bold(true),
+		core.PolicyNever: lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87")).Bold(true),
+	}
+)
+
+// tabSources 用于权限提示中说明数据来源
+var tabSources = map[detailTab]string{
+	tabEnv:     "environ",
+	tabFiles:   "fd",
+	tabLimits:  "limits",
+	tabThreads: "task",
+	tabMaps:    "maps",
+}
+
+func (d *DetailView) contentWidth() int {
+	w := d.width - 12
+	if w < 20 {
+		w = 20
+	}
+	return w
+}
+
+func (d *DetailView) renderOverview() string {
+	p := d.process
+	memMB := float64(p.MemoryUsage) / 1024 / 1024
+
+	portStr := "None"
+	if len(p.Ports) > 0 {
+		var ps []string
+		for _, port := range p.Ports {
+			ps = append(ps, fmt.Sprintf("%d", port))
+		}
+		portStr = strings.Join(ps, ", ")
+	}
+
+	memLabel := "Memory:"
+	if d.chartPSS {
+		memLabel = "PSS:"
+	}
+
+	maxWidth := d.contentWidth()
+
+	cpuVal := fmt.Sprintf("%.1f%%", p.CpuPercent)
+	memVal := fmt.Sprintf("%.1f MB", memMB)
+	if d.chartPSS {
+		memVal = fmt.Sprintf("%.1f MB", float64(p.PSS)/1024/1024)
+	}
+
+	ioVal := "n/a"
+	ioTotals := tabHintStyle.Render("(I/O counters unavailable: permission denied or unsupported)")
+	if p.HasIO {
+		ioVal = fmt.Sprintf("%s/s", core.FormatBytes(uint64(p.IORate())))
+		ioTotals = fmt.Sprintf("R %s/s W %s/s | Read %s (%d calls) | Written %s (%d calls)",
+			core.FormatBytes(uint64(p.IOReadRate)), core.FormatBytes(uint64(p.IOWriteRate)),
+			core.FormatBytes(p.IOReadBytes), p.IOReadOps, core.FormatBytes(p.IOWriteBytes), p.IOWriteOps)
+	}
+
+	memBreakdown := tabHintStyle.Render("(PSS/USS unavailable: permission denied or unsupported)")
+	if p.HasMemDetail {
+		memBreakdown = fmt.Sprintf("RSS %s | PSS %s | USS %s | Swap %s | Shared %s",
+			core.FormatBytes(p.MemoryUsage), core.FormatBytes(p.PSS), core.FormatBytes(p.USS),
+			core.FormatBytes(p.Swap), core.FormatBytes(p.SharedMem))
+	}
+
+	var connSection string
+	if len(d.connections) > 0 {
+		// 有数据：显示表头和前几条
+		lines := []string{connHeaderStyle.Render(fmt.Sprintf("%-6s | %-21s | %-21s | %s", "Proto", "Local", "Remote", "Status"))}
+
+		limit := 5 // 只显示前 5 条
+		for i, c := range d.connections {
+			if i >= limit {
+				lines = append(lines, connRowStyle.Render(fmt.Sprintf("... and %d more", len(d.connections)-limit)))
+				break
+			}
+			// 处理 0.0.0.0
+			remote := fmt.Sprintf("%s:%d", c.RemoteIP, c.RemotePort)
+			if c.RemotePort == 0 {
+				remote = "*"
+			}
+
+			row := fmt.Sprintf("%-6s | %-21s | %-21s | %s", "TCP",
+				fmt.Sprintf("%s:%d", c.LocalIP, c.LocalPort),
+				remote,
+				c.Status,
+			)
+			lines = append(lines, connRowStyle.Render(row))
+		}
+		connSection = "\n\n" + strings.Join(lines, "\n")
+	} else {
+		// 无数据：提示可能是权限问题
+		connSection = "\n\n" + connRowStyle.Render("(No connections or permission denied. Try sudo?)")
+	}
+
+	cmdDisplay := truncate(p.Cmdline, maxWidth)
+
+	cmdStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#A0A0A0")).
+		Width(maxWidth).
+		Align(lipgloss.Left)
+
+	rows := []string{
+		fmt.Sprintf("%s %s", labelStyle.Render("Name:"), p.Name),
+		fmt.Sprintf("%s %d", labelStyle.Render("PID:"), p.PID),
+		fmt.Sprintf("%s %s (%s)", labelStyle.Render("Port:"), portStr, p.Protocol),
+		fmt.Sprintf("%s %s", labelStyle.Render("User:"), p.User),
+	}
+	if p.Catalog != nil {
+		rows = append(rows, d.renderCatalog(p.Catalog, maxWidth)...)
+	}
+	rows = append(rows,
+		"",
+		fmt.Sprintf("%s %-12s %s %s", labelStyle.Render("CPU:"), cpuVal, labelStyle.Render("Disk I/O:"), ioVal),
+		fmt.Sprintf("%s %s", labelStyle.Render(""), ioTotals),
+		d.renderActivityChart(maxWidth),
+		fmt.Sprintf("%s %-12s %s", labelStyle.Render(memLabel), memVal, memBreakdown),
+		d.renderMemoryChart(maxWidth),
+		"",
+		labelStyle.Render("Command:"),
+		cmdStyle.Render(cmdDisplay), // 使用截断后的字符串
+		labelStyle.Render("Network:"),
+		connSection,
+	)
+	return strings.Join(rows, "\n")
+}
+
+// renderCatalog 进程目录中的说明、负责团队、处理方式与 runbook
+func (d *DetailView) renderCatalog(e *core.CatalogEntry, width int) []string {
+	policy := e.Policy.String()
+	if policy == "" {
+		policy = "-"
+	}
+	owner := e.Owner
+	if owner == "" {
+		owner = "-"
+	}
+	style := catalogPolicyStyles[e.Policy]
+	rows := []string{
+		"",
+		fmt.Sprintf("%s %s", labelStyle.Render("Catalog:"), e.Description),
+		fmt.Sprintf("%s %s  %s %s", labelStyle.Render("Owner:"), owner, labelStyle.Render("Policy:"), style.Render(policy)),
+	}
+	if e.Runbook != "" {
+		rows = append(rows, labelStyle.Render("Runbook:"), lipgloss.NewStyle().Width(width).Render(e.Runbook))
+	}
+	rows = append(rows, tabHintStyle.Render("from "+e.Source))
+	return rows
+}
+
+// chartHeight 根据终端高度分配每张图表的行数
+func (d *DetailView) chartHeight() int {
+	// 概览页除图表以外大约占用 34 行 (含边框与状态栏)，剩余空间两张图平分
+	h := (d.height - 34) / 2
+	if h < 2 {
+		h = 2
+	}
+	if h > 6 {
+		h = 6
+	}
+	return h
+}
+
+func (d *DetailView) newChart(width int) *components.Chart {
+	return &components.Chart{
+		Width:  width - 24, // 两侧 Y 轴标签
+		Height: d.chartHeight(),
+		Window: chartWindows[d.zoom],
+	}
+}
+
+// renderActivityChart CPU (左轴) 与磁盘 I/O (右轴) 叠加在同一张图上
+func (d *DetailView) renderActivityChart(width int) string {
+	c := d.newChart(width)
+	c.Primary = components.Axis{Auto: true, MinSpan: 10, Format: func(v float64) string {
+		return fmt.Sprintf("%.0f%%", v)
+	}}
+	c.Secondary = components.Axis{Auto: true, MinSpan: 1024, Format: func(v float64) string {
+		return core.FormatBytes(uint64(v)) + "/s"
+	}}
+
+	series := []components.Series{{Name: "CPU", Points: d.cpuHistory.Points, Color: cpuColor}}
+	if d.process.HasIO {
+		series = append(series, components.Series{Name: "I/O", Points: d.ioHistory.Points, Color: ioColor, Secondary: true})
+	}
+	legend := fmt.Sprintf("%s  %s", components.Legend(series...),
+		tabHintStyle.Render(fmt.Sprintf("(last %s, z to zoom)", components.FormatWindow(chartWindows[d.zoom]))))
+	return legend + "\n" + c.Render(d.state.Service.Now(), series...)
+}
+
+// renderMemoryChart 绘制 RSS 或 PSS 历史 (m 切换)
+func (d *DetailView) renderMemoryChart(width int) string {
+	c := d.newChart(width)
+	c.Primary = components.Axis{Auto: true, MinSpan: 1024 * 1024, Format: func(v float64) string {
+		return core.FormatBytes(uint64(v))
+	}}
+
+	s := components.Series{Name: "RSS", Points: d.memHistory.Points, Color: memColor}
+	if d.chartPSS {
+		s = components.Series{Name: "PSS", Points: d.pssHistory.Points, Color: memColor}
+	}
+	return c.Render(d.state.Service.Now(), s)
+}
+
+// renderTab 渲染除 Overview 以外的列表型 Tab
+func (d *DetailView) renderTab() string {
+	t := d.activeTab
+	if !d.loaded[t] {
+		return tabHintStyle.Render("Loading...")
+	}
+	if err := d.errs[t]; err != nil {
+		return d.renderTabError(t, err)
+	}
+
+	var header string
+	var lines []string
+	switch t {
+	case tabEnv:
+		header, lines = d.envLines()
+	case tabFiles:
+		header, lines = d.fileLines()
+	case tabLimits:
+		header, lines = d.limitLines()
+	case tabThreads:
+		header, lines = d.threadLines()
+	case tabMaps:
+		header, lines = d.mapLines()
+	}
+
+	if len(lines) == 0 {
+		lines = []string{tabHintStyle.Render("(empty)")}
+	}
+
+	// 按滚动位置截取可见区域
+	height := d.bodyHeight()
+	if d.scroll > len(lines)-height {
+		d.scroll = len(lines) - height
+	}
+	if d.scroll < 0 {
+		d.scroll = 0
+	}
+	end := d.scroll + height
+	if end > len(lines) {
+		end = len(lines)
+	}
+	visible := lines[d.scroll:end]
+
+	footer := tabHintStyle.Render(fmt.Sprintf("%d-%d of %d", d.scroll+1, end, len(lines)))
+
+	parts := []string{}
+	if header != "" {
+		parts = append(parts, header)
+	}
+	parts = append(parts, visible...)
+	parts = append(parts, "", footer)
+	return strings.Join(parts, "\n")
+}
+
+func (d *DetailView) renderTabError(t detailTab, err error) string {
+	switch {
+	case errors.Is(err, core.ErrPermissionDenied):
+		return tabErrorStyle.Render(fmt.Sprintf("🔒 Permission denied reading /proc/%d/%s.", d.process.PID, tabSources[t])) +
+			"\n\n" + tabHintStyle.Render("The process belongs to another user. Try running quell with sudo.")
+	case errors.Is(err, core.ErrNotSupported):
+		return tabHintStyle.Render(fmt.Sprintf("%s is not available on this platform.", detailTabNames[t]))
+	default:
+		return tabErrorStyle.Render(fmt.Sprintf("Error: %v", err))
+	}
+}
+
+func (d *DetailView) envLines() (string, []string) {
+	filter := strings.ToLower(d.search.Value())
+	width := d.contentWidth()
+
+	var lines []string
+	for _, kv := range d.env {
+		if filter != "" && !strings.Contains(strings.ToLower(kv), filter) {
+			continue
+		}
+		k, v, _ := strings.Cut(kv, "=")
+		lines = append(lines, envKeyStyle.Render(k)+"="+truncate(v, width-len(k)-1))
+	}
+
+	header := ""
+	if d.searching || d.search.Value() != "" {
+		header = d.search.View()
+	}
+	return header, lines
+}
+
+func (d *DetailView) fileLines() (string, []string) {
+	width := d.contentWidth()
+	header := connHeaderStyle.Render(fmt.Sprintf("%-5s %-10s %-10s %-10s %s", "FD", "Type", "Size", "Offset", "Path"))
+
+	var lines []string
+	for _, f := range d.files {
+		size := "-"
+		if f.Type == "file" {
+			size = core.FormatBytes(uint64(f.Size))
+		}
+		row := fmt.Sprintf("%-5d %-10s %-10s %-10d %s", f.Fd, f.Type, size, f.Offset, f.Path)
+		if f.Deleted {
+			// 已删除的文件高亮显示，这类 fd 会一直占用磁盘空间
+			lines = append(lines, tabErrorStyle.Render(truncate(row+" (deleted)", width)))
+			continue
+		}
+		lines = append(lines, connRowStyle.Render(truncate(row, width)))
+	}
+	return header, lines
+}
+
+func (d *DetailView) limitLines() (string, []string) {
+	header := connHeaderStyle.Render(fmt.Sprintf("%-26s %-14s %-14s %s", "Resource", "Soft", "Hard", "Unit"))
+
+	var lines []string
+	for _, l := range d.limits {
+		lines = append(lines, connRowStyle.Render(fmt.Sprintf("%-26s %-14s %-14s %s", l.Resource, l.Soft, l.Hard, l.Unit)))
+	}
+	return header, lines
+}
+
+func (d *DetailView) threadLines() (string, []string) {
+	header := connHeaderStyle.Render(fmt.Sprintf("%-8s %-18s %-6s %8s %10s", "TID", "Name", "State", "CPU%", "Time"))
+
+	var lines []string
+	for _, t := range d.threads {
+		row := fmt.Sprintf("%-8d %-18s %-6s %7.1f%% %9.1fs",
+			t.TID, truncate(t.Name, 18), t.State, d.threadCPU[t.TID], t.CPUTime)
+		lines = append(lines, connRowStyle.Render(row))
+	}
+	return header, lines
+}
+
+func (d *DetailView) mapLines() (string, []string) {
+	filter := strings.ToLower(d.search.Value())
+	width := d.contentWidth()
+	header := connHeaderStyle.Render(fmt.Sprintf("%-25s %-5s %9s %s", "Range", "Perms", "Size", "Path"))
+	if d.searching || filter != "" {
+		header = d.search.View() + "\n" + header
+	}
+
+	var lines []string
+	for _, m := range d.maps {
+		if filter != "" && !strings.Contains(strings.ToLower(m.Path), filter) {
+			continue
+		}
+		row := fmt.Sprintf("%012x-%012x %-5s %9s %s", m.Start, m.End, m.Perms, core.FormatBytes(m.Size()), m.Path)
+		if m.Deleted {
+			// 旧版本的库仍在内存中，进程需要重启才能用上补丁
+			lines = append(lines, tabErrorStyle.Render(truncate(row+" (deleted)", width)))
+			continue
+		}
+		lines = append(lines, connRowStyle.Render(truncate(row, width)))
+	}
+	return header, lines
+}
+
+// truncate 按字符数截断，超出部分用省略号表示
+func truncate(s string, max int) string {
+	r := []rune(s)
+	if max <= 3 || len(r) <= max {
+		return s
+	}
+	return string(r[:max-3]) + "..."
+}
@@ -2,11 +2,12 @@ package pages
 
 import (
 	"fmt"
-	"strings"
+	"time"
 
 	"github.com/Microindole/quell/internal/core"
 	"github.com/Microindole/quell/internal/tui/components" // 引入组件包
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -14,47 +15,115 @@ import (
 var (
 	detailTitleStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FAFAFA")).Background(lipgloss.Color("#7D56F4")).Padding(0, 1).Bold(true)
 	labelStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4")).Bold(true).Width(10)
-	detailBoxStyle   = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#7D56F4")).Padding(1, 2).MarginTop(1)
+	detailBoxStyle   = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#7D56F4")).Padding(1, 2)
 	cpuColor         = lipgloss.Color("#04B575")
 	memColor         = lipgloss.Color("#7D56F4")
+	ioColor          = lipgloss.Color("#FFA500")
 	connHeaderStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#FAFAFA")).Background(lipgloss.Color("#626262")).Padding(0, 1)
 	connRowStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("#A0A0A0"))
+	tabActiveStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#FAFAFA")).Background(lipgloss.Color("#7D56F4")).Padding(0, 1).Bold(true)
+	tabInactiveStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#A0A0A0")).Padding(0, 1)
+	tabBarStyle      = lipgloss.NewStyle().MarginTop(1)
 )
 
-const maxHistory = 40
+// 历史数据保留一小时，图表可在以下时间窗口间缩放
+const historyRetention = time.Hour
+
+var chartWindows = []time.Duration{time.Minute, 10 * time.Minute, time.Hour}
 
 type ProcessConnectionsMsg []core.Connection
 
+// processHistoryMsg 携带从历史文件中读取的、打开详情页之前的采样
+type processHistoryMsg struct {
+	pid     int32
+	samples []core.HistorySample
+}
+
+// detailTab 标识 DetailView 的各个标签页
+type detailTab int
+
+const (
+	tabOverview detailTab = iota
+	tabEnv
+	tabFiles
+	tabLimits
+	tabThreads
+	tabMaps
+)
+
+var detailTabNames = []string{"Overview", "Environment", "Files", "Limits", "Threads", "Maps"}
+
+// detailTabMsg 携带某个 Tab 懒加载的结果
+type detailTabMsg struct {
+	pid  int32
+	tab  detailTab
+	data interface{}
+	err  error
+}
+
 type DetailView struct {
 	state       *SharedState
 	registry    *HandlerRegistry
 	process     *core.Process
-	cpuHistory  []float64
-	memHistory  []float64
+	cpuHistory  *components.TimeSeries
+	memHistory  *components.TimeSeries // 字节
+	pssHistory  *components.TimeSeries // 字节
+	ioHistory   *components.TimeSeries // 读写速率之和 (字节/秒)
+	chartPSS    bool                   // 内存图表显示 PSS 而不是 RSS
+	zoom        int                    // chartWindows 下标
 	width       int
-	cpuChart    *components.Sparkline
-	memChart    *components.Sparkline
+	height      int
 	connections []core.Connection
+
+	// Tab 状态
+	activeTab detailTab
+	scroll    int
+	loaded    map[detailTab]bool
+	errs      map[detailTab]error
+
+	// 各 Tab 的数据
+	env       []string
+	search    textinput.Model // Env / Maps 共用的过滤输入框
+	searching bool
+	files     []core.OpenFile
+	limits    []core.Limit
+	threads   []core.Thread
+	maps      []core.MemoryMap
+	threadCPU map[int32]float64 // TID -> CPU%
+	prevCPU   map[int32]float64 // TID -> 上次采样的累计 CPU 时间
+	prevAt    time.Time
 }
 
-func NewDetailView(p *core.Process, state *SharedState, width int) *DetailView {
+func NewDetailView(p *core.Process, state *SharedState, width, height int) *DetailView {
+	search := textinput.New()
+	search.Placeholder = "filter..."
+	search.Prompt = "/ "
+	search.CharLimit = 64
+
 	d := &DetailView{
 		state:       state,
 		registry:    &HandlerRegistry{},
 		process:     p,
-		cpuHistory:  make([]float64, maxHistory),
-		memHistory:  make([]float64, maxHistory),
+		cpuHistory:  components.NewTimeSeries(historyRetention),
+		memHistory:  components.NewTimeSeries(historyRetention),
+		pssHistory:  components.NewTimeSeries(historyRetention),
+		ioHistory:   components.NewTimeSeries(historyRetention),
 		width:       width,
-		cpuChart:    components.NewSparkline(lipgloss.NewStyle().Foreground(cpuColor)),
-		memChart:    components.NewSparkline(lipgloss.NewStyle().Foreground(memColor)),
+		height:      height,
 		connections: nil,
+		loaded:      make(map[detailTab]bool),
+		errs:        make(map[detailTab]error),
+		search:      search,
+		threadCPU:   make(map[int32]float64),
 	}
+	// 用进入详情页时的数值作为第一个采样点，图表从第一帧起就有意义
+	d.recordSample(p, state.Service.Now())
 	d.registerActions()
 	return d
 }
 
 func (d *DetailView) Init() tea.Cmd {
-	return d.fetchConnectionsCmd()
+	return tea.Batch(d.fetchConnectionsCmd(), d.loadHistoryCmd())
 }
 
 func (d *DetailView) Update(msg tea.Msg) (View, tea.Cmd) {
@@ -62,28 +131,50 @@ func (d *DetailView) Update(msg tea.Msg) (View, tea.Cmd) {
 
 	case tea.WindowSizeMsg:
 		d.width = msg.Width
+		d.height = msg.Height
 		return d, nil
 
 	case TickMsg:
-		return d, d.refreshProcessCmd()
+		cmds := []tea.Cmd{d.refreshProcessCmd()}
+		// 变化较快的 Tab 跟随心跳刷新
+		if d.activeTab == tabFiles || d.activeTab == tabThreads {
+			cmds = append(cmds, d.loadTabCmd(d.activeTab))
+		}
+		return d, tea.Batch(cmds...)
+
+	case ForceRefreshMsg:
+		return d, tea.Batch(d.refreshProcessCmd(), d.fetchConnectionsCmd())
 
 	case *core.Process:
 		d.process = msg
-		// 更新数据历史
-		d.cpuHistory = d.cpuHistory[1:]
-		d.cpuHistory = append(d.cpuHistory, msg.CpuPercent)
-
-		memMB := float64(msg.MemoryUsage) / 1024 / 1024
-		d.memHistory = d.memHistory[1:]
-		d.memHistory = append(d.memHistory, memMB)
-
+		d.recordSample(msg, d.state.Service.Now())
 		return d, nil
 
 	case ProcessConnectionsMsg:
 		d.connections = msg
 		return d, nil
 
+	case processHistoryMsg:
+		if msg.pid == d.process.PID {
+			d.backfillHistory(msg.samples)
+		}
+		return d, nil
+
+	case detailTabMsg:
+		if msg.pid != d.process.PID {
+			return d, nil
+		}
+		d.applyTabData(msg)
+		return d, nil
+
 	case tea.KeyMsg:
+		if d.searching {
+			return d, d.updateSearch(msg)
+		}
+		// 数字键直接跳转 Tab
+		if s := msg.String(); len(s) == 1 && s[0] >= '1' && s[0] < '1'+byte(len(detailTabNames)) {
+			return d, d.switchTab(detailTab(s[0] - '1'))
+		}
 		if cmd, handled := d.registry.Handle(msg, d); handled {
 			return d, cmd
 		}
@@ -91,6 +182,29 @@ func (d *DetailView) Update(msg tea.Msg) (View, tea.Cmd) {
 	return d, nil
 }
 
+func (d *DetailView) updateSearch(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEsc:
+		// 第一次 Esc 清空，再按一次退出搜索
+		if d.search.Value() != "" {
+			d.search.SetValue("")
+		} else {
+			d.searching = false
+			d.search.Blur()
+		}
+		d.scroll = 0
+		return nil
+	case tea.KeyEnter:
+		d.searching = false
+		d.search.Blur()
+		return nil
+	}
+	var cmd tea.Cmd
+	d.search, cmd = d.search.Update(msg)
+	d.scroll = 0
+	return cmd
+}
+
 func (d *DetailView) registerActions() {
 	// Back
 	d.registry.Register(key.NewBinding(key.WithKeys("esc", "backspace"), key.WithHelp("esc", "back")),
@@ -98,23 +212,209 @@ func (d *DetailView) registerActions() {
 			return Pop(), true
 		})
 
-	// Kill
+	// Kill：确认后回到列表，由列表显示结果
 	d.registry.Register(key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "kill")),
 		func(m View) (tea.Cmd, bool) {
-			cmd := tea.Batch(
-				Pop(),
-				Pop(),
-				func() tea.Msg {
-					return ProcessActionMsg{
-						Err:    d.state.Service.Kill(d.process.PID, false),
-						Action: "Killed",
-					}
-				},
-			)
-			return Push(NewConfirmDialog(fmt.Sprintf("Kill %s?", d.process.Name), cmd)), true
+			p := *d.process
+			choices := SignalChoices(func(op Operation) tea.Cmd {
+				return tea.Sequence(Pop(), func() tea.Msg { return op.Run(d.state.Service, p.PID) })
+			})
+			return ConfirmAction(d.state, fmt.Sprintf("Signal %s (PID %d)?", p.Name, p.PID), []core.Process{p}, 0, choices...), true
+		})
+
+	// 切换 Tab
+	d.registry.Register(key.NewBinding(key.WithKeys("tab", "right", "l"), key.WithHelp("tab/1-6", "next tab")),
+		func(m View) (tea.Cmd, bool) {
+			return d.switchTab((d.activeTab + 1) % detailTab(len(detailTabNames))), true
+		})
+	d.registry.Register(key.NewBinding(key.WithKeys("shift+tab", "left", "h"), key.WithHelp("shift+tab", "prev tab")),
+		func(m View) (tea.Cmd, bool) {
+			n := detailTab(len(detailTabNames))
+			return d.switchTab((d.activeTab + n - 1) % n), true
+		})
+
+	// 滚动
+	d.registry.Register(key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/↓", "scroll")),
+		func(m View) (tea.Cmd, bool) {
+			if d.scroll > 0 {
+				d.scroll--
+			}
+			return nil, true
+		})
+	d.registry.Register(key.NewBinding(key.WithKeys("down", "j")),
+		func(m View) (tea.Cmd, bool) {
+			d.scroll++
+			return nil, true
+		})
+	d.registry.Register(key.NewBinding(key.WithKeys("pgup")),
+		func(m View) (tea.Cmd, bool) {
+			d.scroll -= d.bodyHeight()
+			if d.scroll < 0 {
+				d.scroll = 0
+			}
+			return nil, true
+		})
+	d.registry.Register(key.NewBinding(key.WithKeys("pgdown")),
+		func(m View) (tea.Cmd, bool) {
+			d.scroll += d.bodyHeight()
+			return nil, true
+		})
+
+	// 环境变量 / 内存映射搜索
+	d.registry.Register(key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search")),
+		func(m View) (tea.Cmd, bool) {
+			if d.activeTab != tabEnv && d.activeTab != tabMaps {
+				return nil, false
+			}
+			d.searching = true
+			return d.search.Focus(), true
+		})
+
+	// 内存波形图在 RSS / PSS 之间切换
+	d.registry.Register(key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "RSS/PSS chart")),
+		func(m View) (tea.Cmd, bool) {
+			if d.activeTab != tabOverview {
+				return nil, false
+			}
+			d.chartPSS = !d.chartPSS
+			return nil, true
+		})
+
+	// 图表时间窗口缩放：1m / 10m / 1h
+	d.registry.Register(key.NewBinding(key.WithKeys("z"), key.WithHelp("z", "zoom")),
+		func(m View) (tea.Cmd, bool) {
+			if d.activeTab != tabOverview {
+				return nil, false
+			}
+			d.zoom = (d.zoom + 1) % len(chartWindows)
+			return nil, true
+		})
+
+	// 重新加载当前 Tab
+	d.registry.Register(key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "reload")),
+		func(m View) (tea.Cmd, bool) {
+			if d.activeTab == tabOverview {
+				return d.fetchConnectionsCmd(), true
+			}
+			return d.loadTabCmd(d.activeTab), true
 		})
 }
 
+// recordSample 把一次刷新得到的数值追加到历史中
+func (d *DetailView) recordSample(p *core.Process, now time.Time) {
+	d.cpuHistory.Add(now, p.CpuPercent)
+	d.memHistory.Add(now, float64(p.MemoryUsage))
+	d.pssHistory.Add(now, float64(p.PSS))
+	d.ioHistory.Add(now, p.IORate())
+}
+
+// loadHistoryCmd 读取历史文件中该进程最近一小时的采样，没有开启记录时忽略
+func (d *DetailView) loadHistoryCmd() tea.Cmd {
+	pid, ct := d.process.PID, d.process.CreateTime
+	svc := d.state.Service
+	return func() tea.Msg {
+		samples, err := svc.ProcessHistory(pid, ct, svc.Now().Add(-historyRetention))
+		if err != nil || len(samples) == 0 {
+			return nil
+		}
+		return processHistoryMsg{pid: pid, samples: samples}
+	}
+}
+
+func (d *DetailView) backfillHistory(samples []core.HistorySample) {
+	cpu := make([]components.Point, len(samples))
+	mem := make([]components.Point, len(samples))
+	io := make([]components.Point, 0, len(samples))
+	for i, s := range samples {
+		cpu[i] = components.Point{T: s.Time, V: s.CPU}
+		mem[i] = components.Point{T: s.Time, V: float64(s.RSS)}
+		if s.HasIO {
+			io = append(io, components.Point{T: s.Time, V: s.IORead + s.IOWrite})
+		}
+	}
+	d.cpuHistory.Backfill(cpu)
+	d.memHistory.Backfill(mem)
+	d.ioHistory.Backfill(io)
+}
+
+// switchTab 切换 Tab，首次进入时才触发加载
+func (d *DetailView) switchTab(t detailTab) tea.Cmd {
+	if t == d.activeTab {
+		return nil
+	}
+	d.activeTab = t
+	d.scroll = 0
+	d.search.SetValue("")
+	if t == tabOverview || d.loaded[t] {
+		return nil
+	}
+	return d.loadTabCmd(t)
+}
+
+func (d *DetailView) loadTabCmd(t detailTab) tea.Cmd {
+	pid := d.process.PID
+	svc := d.state.Service
+	return func() tea.Msg {
+		msg := detailTabMsg{pid: pid, tab: t}
+		switch t {
+		case tabEnv:
+			msg.data, msg.err = svc.GetEnviron(pid)
+		case tabFiles:
+			msg.data, msg.err = svc.GetOpenFiles(pid)
+		case tabLimits:
+			msg.data, msg.err = svc.GetLimits(pid)
+		case tabThreads:
+			msg.data, msg.err = svc.GetThreads(pid)
+		case tabMaps:
+			msg.data, msg.err = svc.GetMemoryMaps(pid)
+		default:
+			return nil
+		}
+		return msg
+	}
+}
+
+func (d *DetailView) applyTabData(msg detailTabMsg) {
+	d.loaded[msg.tab] = true
+	d.errs[msg.tab] = msg.err
+	if msg.err != nil {
+		return
+	}
+
+	switch msg.tab {
+	case tabEnv:
+		d.env, _ = msg.data.([]string)
+	case tabFiles:
+		d.files, _ = msg.data.([]core.OpenFile)
+	case tabLimits:
+		d.limits, _ = msg.data.([]core.Limit)
+	case tabThreads:
+		d.threads, _ = msg.data.([]core.Thread)
+		d.updateThreadCPU()
+	case tabMaps:
+		d.maps, _ = msg.data.([]core.MemoryMap)
+	}
+}
+
+// updateThreadCPU 根据两次采样之间的 CPU 时间差计算每个线程的占用率
+func (d *DetailView) updateThreadCPU() {
+	now := d.state.Service.Now()
+	elapsed := now.Sub(d.prevAt).Seconds()
+	current := make(map[int32]float64, len(d.threads))
+
+	for _, t := range d.threads {
+		current[t.TID] = t.CPUTime
+		if prev, ok := d.prevCPU[t.TID]; ok && elapsed > 0 {
+			d.threadCPU[t.TID] = (t.CPUTime - prev) / elapsed * 100
+		} else {
+			d.threadCPU[t.TID] = 0
+		}
+	}
+
+	d.prevCPU = current
+	d.prevAt = now
+}
+
 func (d *DetailView) refreshProcessCmd() tea.Cmd {
 	return func() tea.Msg {
 		procs, err := d.state.Service.GetProcesses()
@@ -124,6 +424,10 @@ func (d *DetailView) refreshProcessCmd() tea.Cmd {
 		for _, p := range procs {
 			if p.PID == d.process.PID {
 				newP := p
+				// 详情页只关心一个进程，总是采集精确内存
+				if md, err := d.state.Service.GetMemoryDetail(p.PID); err == nil {
+					newP.ApplyMemoryDetail(md)
+				}
 				return &newP
 			}
 		}
@@ -141,85 +445,39 @@ func (d *DetailView) fetchConnectionsCmd() tea.Cmd {
 	}
 }
 
+// bodyHeight 估算 Tab 内容区可显示的行数
+func (d *DetailView) bodyHeight() int {
+	// 标题、Tab 栏、边框与内边距、状态栏
+	h := d.height - 12
+	if h < 5 {
+		h = 15
+	}
+	return h
+}
+
 func (d *DetailView) View() string {
 	p := d.process
-	memMB := float64(p.MemoryUsage) / 1024 / 1024
 
-	portStr := "None"
-	if len(p.Ports) > 0 {
-		var ps []string
-		for _, port := range p.Ports {
-			ps = append(ps, fmt.Sprintf("%d", port))
+	var tabs []string
+	for i, name := range detailTabNames {
+		label := fmt.Sprintf("%d %s", i+1, name)
+		if detailTab(i) == d.activeTab {
+			tabs = append(tabs, tabActiveStyle.Render(label))
+		} else {
+			tabs = append(tabs, tabInactiveStyle.Render(label))
 		}
-		portStr = strings.Join(ps, ", ")
 	}
-
-	cpuGraph := d.cpuChart.Render(d.cpuHistory)
-	memGraph := d.memChart.Render(d.memHistory)
-
-	maxWidth := d.width - 12
-	if maxWidth < 20 {
-		maxWidth = 20
+	tabBar := tabBarStyle.Render(lipgloss.JoinHorizontal(lipgloss.Top, tabs...))
+
+	var body string
+	switch d.activeTab {
+	case tabOverview:
+		body = d.renderOverview()
+	default:
+		body = d.renderTab()
 	}
 
-	cpuVal := fmt.Sprintf("%.1f%%", p.CpuPercent)
-	memVal := fmt.Sprintf("%.1f MB", memMB)
-
-	var connSection string
-	if len(d.connections) > 0 {
-		// 有数据：显示表头和前几条
-		lines := []string{connHeaderStyle.Render(fmt.Sprintf("%-6s | %-21s | %-21s | %s", "Proto", "Local", "Remote", "Status"))}
-
-		limit := 5 // 只显示前 5 条
-		for i, c := range d.connections {
-			if i >= limit {
-				lines = append(lines, connRowStyle.Render(fmt.Sprintf("... and %d more", len(d.connections)-limit)))
-				break
-			}
-			// 处理 0.0.0.0
-			remote := fmt.Sprintf("%s:%d", c.RemoteIP, c.RemotePort)
-			if c.RemotePort == 0 {
-				remote = "*"
-			}
-
-			row := fmt.Sprintf("%-6s | %-21s | %-21s | %s", "TCP",
-				fmt.Sprintf("%s:%d", c.LocalIP, c.LocalPort),
-				remote,
-				c.Status,
-			)
-			lines = append(lines, connRowStyle.Render(row))
-		}
-		connSection = "\n\n" + strings.Join(lines, "\n")
-	} else {
-		// 无数据：提示可能是权限问题
-		connSection = "\n\n" + connRowStyle.Render("(No connections or permission denied. Try sudo?)")
-	}
-
-	cmdDisplay := p.Cmdline
-	if len(cmdDisplay) > maxWidth {
-		cmdDisplay = cmdDisplay[:maxWidth-3] + "..."
-	}
-
-	cmdStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#A0A0A0")).
-		Width(maxWidth).
-		Align(lipgloss.Left)
-
-	rows := []string{
-		fmt.Sprintf("%s %s", labelStyle.Render("Name:"), p.Name),
-		fmt.Sprintf("%s %d", labelStyle.Render("PID:"), p.PID),
-		fmt.Sprintf("%s %s (%s)", labelStyle.Render("Port:"), portStr, p.Protocol),
-		fmt.Sprintf("%s %s", labelStyle.Render("User:"), p.User),
-		"",
-		fmt.Sprintf("%s %-12s %s", labelStyle.Render("CPU:"), cpuVal, cpuGraph),
-		fmt.Sprintf("%s %-12s %s", labelStyle.Render("Memory:"), memVal, memGraph),
-		"",
-		labelStyle.Render("Command:"),
-		cmdStyle.Render(cmdDisplay), // 使用截断后的字符串
-		labelStyle.Render("Network:"),
-		connSection,
-	}
-	return detailTitleStyle.Render(fmt.Sprintf(" Process Detail: %s ", p.Name)) + "\n" + detailBoxStyle.Render(strings.Join(rows, "\n"))
+	return detailTitleStyle.Render(fmt.Sprintf(" Process Detail: %s ", p.Name)) + "\n" + tabBar + "\n" + detailBoxStyle.Render(body)
 }
 
 func (d *DetailView) ShortHelp() []key.Binding { return d.registry.MakeHelp() }
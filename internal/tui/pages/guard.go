@@ -0,0 +1,214 @@
+package pages
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+
+	"github.com/Microindole/quell/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ConfirmAction 所有会向进程发送信号的流程都经过这里，弹出 ActionDialog：
+//   - 只读模式下直接报错，不弹框
+//   - 单个进程被保护机制拒绝时直接报错；批量时被拒绝的进程列在框里，执行时由 Service 逐个拦下
+//   - 有需要确认的进程时要求输入进程名 (批量时输入 yes)，通过后记录确认再执行
+//
+// choices 中 Action 为 nil 的按钮表示取消，focus 是初始焦点
+func ConfirmAction(state *SharedState, title string, procs []core.Process, focus int, choices ...Choice) tea.Cmd {
+	if state.Service.Mode() == core.ModeReadOnly {
+		return func() tea.Msg { return ProcessActionMsg{Err: core.ErrReadOnly} }
+	}
+
+	impacts := make([]core.Impact, len(procs))
+	var guarded []core.Process
+	for i, p := range procs {
+		impacts[i] = state.Service.AssessImpact(p)
+		switch v := impacts[i].Verdict; v.Level {
+		case core.GuardRefuse:
+			if len(procs) == 1 {
+				err := &core.SafeguardError{PID: p.PID, Name: p.Name, Verdict: v}
+				return func() tea.Msg { return ProcessActionMsg{Err: err} }
+			}
+		case core.GuardConfirm:
+			guarded = append(guarded, p)
+		}
+	}
+
+	if title == "" {
+		title = fmt.Sprintf("Signal %d processes?", len(procs))
+		if len(procs) == 1 {
+			title = fmt.Sprintf("Signal %s (%d)?", procs[0].Name, procs[0].PID)
+		}
+	}
+	dialog := NewActionDialog(title, impacts, choices).SetFocus(focus)
+	if len(guarded) > 0 {
+		// 单个进程输入进程名，批量时输入 yes
+		expect := "yes"
+		if len(procs) == 1 {
+			expect = procs[0].Name
+		}
+		dialog.RequireTyped(expect, func() { state.Service.Confirm(guarded...) })
+	}
+	return Push(dialog)
+}
+
+// SignalChoices 终止 / 强制终止 / 暂停 / 取消，供列表、详情页等通用
+// run 为每个按钮生成要执行的命令
+func SignalChoices(run func(op Operation) tea.Cmd) []Choice {
+	return []Choice{
+		OpTerminate.Choice("t", run(OpTerminate)),
+		OpForceKill.Choice("k", run(OpForceKill)),
+		OpSuspend.Choice("s", run(OpSuspend)),
+		CancelChoice,
+	}
+}
+
+// Operation 可以对单个进程或批量执行的操作
+type Operation struct {
+	Label   string // 按钮与结果页上的名字，例如 "Terminate"
+	Verb    string // 确认提示中的动词，例如 "Send SIGHUP to"；为空时使用 Label
+	Past    string // 结果提示，例如 "Killed"
+	Danger  bool   // 确认框中以红色按钮显示
+	Guarded bool   // 是否经过保护检查 (恢复运行不需要)
+	Apply   func(svc *core.Service, pid int32) error
+	Note    func(svc *core.Service, pid int32) string // 可选，单个进程执行成功后附加到结果中的提示
+}
+
+var (
+	OpTerminate = Operation{Label: "Terminate", Past: "Killed", Danger: true, Guarded: true,
+		Apply: func(svc *core.Service, pid int32) error { return svc.Kill(pid, false) }}
+	OpForceKill = Operation{Label: "Kill -9", Past: "Force killed", Danger: true, Guarded: true,
+		Apply: func(svc *core.Service, pid int32) error { return svc.Kill(pid, true) }}
+	OpSuspend = Operation{Label: "Suspend", Past: "Suspended", Guarded: true,
+		Apply: func(svc *core.Service, pid int32) error { return svc.Suspend(pid) }}
+	OpResume = Operation{Label: "Resume", Past: "Resumed",
+		Apply: func(svc *core.Service, pid int32) error { return svc.Resume(pid) }}
+)
+
+// OpSignal 发送任意信号；SIGCONT 与恢复运行一样不经过保护检查
+func OpSignal(sig syscall.Signal) Operation {
+	name := core.SignalName(sig)
+	return Operation{
+		Label:   "Send " + name,
+		Verb:    "Send " + name + " to",
+		Past:    "Sent " + name,
+		Danger:  name != "SIGCONT",
+		Guarded: name != "SIGCONT",
+		Apply:   func(svc *core.Service, pid int32) error { return svc.Signal(pid, sig) },
+	}
+}
+
+// OpRenice 修改 nice 值；与恢复运行一样不经过保护检查
+func OpRenice(nice int) Operation {
+	return Operation{
+		Label: fmt.Sprintf("Renice %d", nice),
+		Verb:  fmt.Sprintf("Set nice %d on", nice),
+		Past:  fmt.Sprintf("Set nice %d", nice),
+		Apply: func(svc *core.Service, pid int32) error { return svc.Renice(pid, nice) },
+	}
+}
+
+// OpIONice 修改 I/O 调度类别与级别
+func OpIONice(prio core.IOPriority) Operation {
+	return Operation{
+		Label: "I/O " + prio.String(),
+		Verb:  "Set I/O priority " + prio.String() + " on",
+		Past:  "Set I/O priority " + prio.String(),
+		Apply: func(svc *core.Service, pid int32) error { return svc.SetIOPriority(pid, prio) },
+	}
+}
+
+// OpAffinity 设置 CPU 亲和性，threads 为 true 时包括所有线程；不经过保护检查
+// 成功后提示 cpus 与进程所在 cgroup 的 cpuset 不一致的地方
+func OpAffinity(cpus core.CPUSet, threads bool) Operation {
+	suffix := ""
+	if threads {
+		suffix = " (all threads)"
+	}
+	return Operation{
+		Label: "Pin to " + cpus.String() + suffix,
+		Verb:  "Set CPU affinity " + cpus.String() + suffix + " on",
+		Past:  "Set CPU affinity " + cpus.String() + suffix,
+		Apply: func(svc *core.Service, pid int32) error { return svc.SetAffinity(pid, cpus, threads) },
+		Note: func(svc *core.Service, pid int32) string {
+			info, err := svc.GetAffinity(pid)
+			if err != nil {
+				return ""
+			}
+			return strings.Join(info.Warnings(cpus), "; ")
+		},
+	}
+}
+
+// Then 依次执行 o 与 next，o 失败时不再执行 next
+func (o Operation) Then(next Operation) Operation {
+	return Operation{
+		Label:   o.Label + " + " + next.Label,
+		Past:    o.Past + ", " + strings.ToLower(next.Past[:1]) + next.Past[1:],
+		Danger:  o.Danger || next.Danger,
+		Guarded: o.Guarded || next.Guarded,
+		Apply: func(svc *core.Service, pid int32) error {
+			if err := o.Apply(svc, pid); err != nil {
+				return err
+			}
+			return next.Apply(svc, pid)
+		},
+	}
+}
+
+// Prompt 对 n 个进程执行前的确认提示，例如 "Send SIGHUP to 3 selected processes?"
+func (o Operation) Prompt(n int) string {
+	return fmt.Sprintf("%s %d selected processes?", o.verb(), n)
+}
+
+func (o Operation) verb() string {
+	if o.Verb != "" {
+		return o.Verb
+	}
+	return o.Label
+}
+
+// Run 对单个进程执行，结果以 ProcessActionMsg 返回
+func (o Operation) Run(svc *core.Service, pid int32) ProcessActionMsg {
+	msg := ProcessActionMsg{Err: o.Apply(svc, pid), Action: o.Past}
+	if msg.Err == nil && o.Note != nil {
+		msg.Note = o.Note(svc, pid)
+	}
+	return msg
+}
+
+// Choice 确认框中执行 action 的按钮
+func (o Operation) Choice(key string, action tea.Cmd) Choice {
+	return Choice{Label: o.Label, Key: key, Action: action, Danger: o.Danger}
+}
+
+// GuardedAction 只有一个操作的确认流程：prompt 为空表示原本无需确认，
+// 目标都没有风险时直接执行，否则与 prompt 非空时一样弹出确认框
+func GuardedAction(state *SharedState, procs []core.Process, prompt string, action tea.Cmd) tea.Cmd {
+	if prompt == "" && state.Service.Mode() != core.ModeReadOnly {
+		risky := false
+		for _, p := range procs {
+			if state.Service.Classify(p).Level != core.GuardNone {
+				risky = true
+				break
+			}
+		}
+		if !risky {
+			return action
+		}
+	}
+	return ConfirmAction(state, prompt, procs, 0,
+		Choice{Label: "Confirm", Key: "y", Action: action, Danger: true},
+		CancelChoice,
+	)
+}
+
+// errorStatus 操作失败时的状态栏文字；演练模式的拦截不算错误，原样显示 "dry run: would ..."
+func errorStatus(err error) string {
+	if errors.Is(err, core.ErrDryRun) {
+		return err.Error()
+	}
+	return fmt.Sprintf("Error: %v", err)
+}
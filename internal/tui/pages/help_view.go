@@ -39,16 +39,51 @@ Global Keys:
 
 List View:
   /           : Filter processes
-  x           : Kill process
+  x           : Kill process (selection: batch)
   X           : Force kill process
+  s / c       : Suspend / continue (selection: batch)
+  a / i       : Select all visible / invert
+  n           : Nice / I/O priority (selection: batch)
+  A           : CPU affinity grid (selection: batch)
   enter/space : Inspect process details
-  tab         : Sort (PID/Mem/CPU)
+  tab         : Sort (Status/CPU/Mem/PID/PSS/USS/Swap/Shared/IO)
+  M           : Collect PSS/USS for all rows
+  H           : Collapse/expand system header
+  G           : CPU/memory history chart in header
+  z           : Zoom chart (1m / 10m / 1h)
   t           : Toggle Tree View
   ` + "`" + `           : Command Mode
 
+Detail View:
+  tab / 1-6   : Overview / Env / Files / Limits / Threads / Maps
+  /           : Search (Env and Maps tabs)
+  r           : Reload current tab
+  m           : Chart PSS instead of RSS
+  z           : Zoom charts (1m / 10m / 1h)
+
+Replay (quell replay <file>):
+  ctrl+p      : Play / pause
+  ctrl+←/→    : Seek 10s
+  shift+←/→   : Seek 1m
+  ctrl+↑/↓    : Faster / slower
+
 Commands (type after pressing ` + "`" + `):
   /help       : Show this help
   /quit       : Exit application
+  /kill       : Kill PIDs (no args: the selection)
+  /pause      : Suspend PIDs (/resume to continue)
+  /signal     : Send a signal, e.g. /signal HUP 1234 5678
+  /renice     : Set nice, e.g. /renice 10 -t 1234 (-t: with children)
+  /ionice     : I/O priority none|idle|be/0-7|rt/0-7 (-t)
+  /affinity   : Pin to CPUs, e.g. /affinity 0-3,8 1234 (-a threads, -t)
+  /pkill      : Preview & signal matches (-x -r -f -u -s -c)
+  /deleted    : Deleted files still held open
+  /who-has    : Processes holding a file, dir or mount
+  /lib        : Processes mapping a library (--deleted)
+  /export     : Export list to JSON/CSV/Markdown
+  /filter     : Filter the list (no args clears)
+  /select     : Select PIDs or a query (all invert tree siblings save recall clear)
+  /log        : Audit log (pid= surface= action= since= failed)
 `
 	return "\n" + helpBoxStyle.Render(content) + "\n"
 }
@@ -3,6 +3,7 @@ package pages
 import (
 	"fmt"
 
+	"github.com/Microindole/quell/internal/core"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -19,7 +20,8 @@ func GetDefaultListActions(v *ListView) []KeyHandler {
 					if w < 10 {
 						w = 80
 					}
-					return Push(NewDetailView(p, v.state, w)), true
+					h := v.processList.Inner().Height() + 4
+					return Push(NewDetailView(p, v.state, w, h)), true
 				}
 				return nil, false
 			},
@@ -30,6 +32,10 @@ func GetDefaultListActions(v *ListView) []KeyHandler {
 			Action: func(m View) (tea.Cmd, bool) {
 				v.currentSortIdx = (v.currentSortIdx + 1) % len(v.sorters)
 				v.updateListItems()
+				// 切换到 PSS/USS 等排序时立即补齐全量数据
+				if v.needsFullMemDetail() {
+					return v.refreshListCmd(), true
+				}
 				return nil, true
 			},
 		},
@@ -42,96 +48,134 @@ func GetDefaultListActions(v *ListView) []KeyHandler {
 				return nil, true
 			},
 		},
-		// 4. 普通杀进程 (x)
+		// 4. 杀进程 (x)：弹出确认框，可改选强制终止或暂停
 		{
 			Binding: key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "kill")),
-			Action: func(m View) (tea.Cmd, bool) {
-				// A. 批量处理
-				if len(v.selectedPids) > 0 {
-					count := len(v.selectedPids)
-					msg := fmt.Sprintf("Kill %d selected processes?", count)
-					var cmds []tea.Cmd
-					for pid := range v.selectedPids {
-						cmds = append(cmds, v.killCmd(pid, false))
-					}
-					cmds = append(cmds, func() tea.Msg { return ClearSelectionMsg{} })
-					return Push(NewConfirmDialog(msg, tea.Batch(cmds...))), true
-				}
-
-				// B. 单个处理
-				if p := v.processList.SelectedItem(); p != nil {
-					return Push(NewConfirmDialog(
-						fmt.Sprintf("Kill process %d (%s)?", p.PID, p.Name),
-						v.killCmd(p.PID, false),
-					)), true
-				}
-				return nil, false
-			},
+			Action:  makeKillAction(v, false),
 		},
-		// 5. 强制杀进程 (X)
+		// 5. 强制杀进程 (X)：同一个确认框，默认选中 Kill -9
 		{
 			Binding: key.NewBinding(key.WithKeys("X"), key.WithHelp("X", "force kill")),
 			Action:  makeKillAction(v, true),
 		},
-		// 6. 暂停进程 (s)
+		// 6. 暂停进程 (s)：有勾选时批量暂停
 		{
 			Binding: key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "suspend")),
+			Action:  makeOpAction(v, OpSuspend),
+		},
+		// 7. 恢复进程 (c)：有勾选时批量恢复
+		{
+			Binding: key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "continue")),
+			Action:  makeOpAction(v, OpResume),
+		},
+		// 8. 折叠/展开系统摘要 (H)
+		{
+			Binding: key.NewBinding(key.WithKeys("H"), key.WithHelp("H", "toggle header")),
 			Action: func(m View) (tea.Cmd, bool) {
-				if p := v.processList.SelectedItem(); p != nil {
-					return func() tea.Msg {
-						return ProcessActionMsg{Err: v.state.Service.Suspend(p.PID), Action: "Suspended"}
-					}, true
-				}
-				return nil, false
+				v.header.Collapsed = !v.header.Collapsed
+				v.resize()
+				return nil, true
 			},
 		},
-		// 7. 恢复进程 (c)
+		// 9. 在摘要中显示 CPU/内存历史图表 (G)
 		{
-			Binding: key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "continue")),
+			Binding: key.NewBinding(key.WithKeys("G"), key.WithHelp("G", "history chart")),
 			Action: func(m View) (tea.Cmd, bool) {
-				if p := v.processList.SelectedItem(); p != nil {
-					return func() tea.Msg {
-						return ProcessActionMsg{Err: v.state.Service.Resume(p.PID), Action: "Resumed"}
-					}, true
+				v.header.ShowChart = !v.header.ShowChart
+				v.resize()
+				return nil, true
+			},
+		},
+		// 10. 图表时间窗口缩放 (z)
+		{
+			Binding: key.NewBinding(key.WithKeys("z"), key.WithHelp("z", "zoom chart")),
+			Action: func(m View) (tea.Cmd, bool) {
+				if !v.header.ShowChart {
+					return nil, false
 				}
-				return nil, false
+				v.header.Zoom = (v.header.Zoom + 1) % len(chartWindows)
+				return nil, true
+			},
+		},
+		// 11. 全量采集精确内存 (M)
+		{
+			Binding: key.NewBinding(key.WithKeys("M"), key.WithHelp("M", "PSS/USS for all")),
+			Action: func(m View) (tea.Cmd, bool) {
+				v.memDetailAll = !v.memDetailAll
+				return v.refreshListCmd(), true
 			},
 		},
-		// 8. 呼出命令输入框 (`)
+		// 12. 呼出命令输入框 (`)
 		{
 			Binding: key.NewBinding(key.WithKeys("`"), key.WithHelp("`", "command")),
 			Action: func(m View) (tea.Cmd, bool) {
 				return Push(NewCommandInput(v.state, "")), true
 			},
 		},
-		// 9. 快速批量查杀 (P)
+		// 13. 快速批量查杀 (P)
 		{
 			Binding: key.NewBinding(key.WithKeys("P"), key.WithHelp("P", "pkill")),
 			Action: func(m View) (tea.Cmd, bool) {
 				return Push(NewCommandInput(v.state, "/pkill ")), true
 			},
 		},
-		// 10. 空格键多选
+		// 14. 空格键多选
 		{
 			Binding: key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "select")),
 			Action: func(m View) (tea.Cmd, bool) {
 				if p := v.processList.SelectedItem(); p != nil {
-					if v.selectedPids[p.PID] {
-						delete(v.selectedPids, p.PID)
-					} else {
-						v.selectedPids[p.PID] = true
-					}
+					v.selected.Toggle(*p)
 					return v.updateListItems(), true
 				}
 				return nil, false
 			},
 		},
-		// 11. 退出逻辑
+		// 15. 选中当前列表 (过滤后) 中的全部进程 (a)
+		{
+			Binding: key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "select visible")),
+			Action: func(m View) (tea.Cmd, bool) {
+				return v.applySelect(SelectMsg{Op: SelectVisible}), true
+			},
+		},
+		// 16. 反选当前列表中的进程 (i)
+		{
+			Binding: key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "invert selection")),
+			Action: func(m View) (tea.Cmd, bool) {
+				return v.applySelect(SelectMsg{Op: SelectInvert}), true
+			},
+		},
+		// 17. 调整优先级 (n)：nice 滑块与 I/O 优先级，有勾选时作用于全部勾选的进程
+		{
+			Binding: key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "nice/ionice")),
+			Action: func(m View) (tea.Cmd, bool) {
+				if len(v.selected) > 0 {
+					return Push(NewPriorityDialog(v.state, v.selectedProcesses())), true
+				}
+				if p := v.processList.SelectedItem(); p != nil {
+					return Push(NewPriorityDialog(v.state, []core.Process{*p})), true
+				}
+				return nil, false
+			},
+		},
+		// 18. CPU 亲和性 (A)：每个核一个格子，有勾选时作用于全部勾选的进程
+		{
+			Binding: key.NewBinding(key.WithKeys("A"), key.WithHelp("A", "affinity")),
+			Action: func(m View) (tea.Cmd, bool) {
+				if len(v.selected) > 0 {
+					return Push(NewAffinityDialog(v.state, v.selectedProcesses())), true
+				}
+				if p := v.processList.SelectedItem(); p != nil {
+					return Push(NewAffinityDialog(v.state, []core.Process{*p})), true
+				}
+				return nil, false
+			},
+		},
+		// 19. 退出逻辑
 		{
 			Binding: key.NewBinding(key.WithKeys("esc", "q"), key.WithHelp("esc/q", "quit")),
 			Action: func(m View) (tea.Cmd, bool) {
-				if len(v.selectedPids) > 0 {
-					v.selectedPids = make(map[int32]bool)
+				if len(v.selected) > 0 {
+					v.selected = make(Selection)
 					return v.updateListItems(), true
 				}
 				return Push(NewConfirmDialog("Quit application?", tea.Quit)), true
@@ -142,14 +186,43 @@ func GetDefaultListActions(v *ListView) []KeyHandler {
 
 // 辅助函数 unwrapProcess 不再需要，可以删除
 
+// makeKillAction 有勾选时针对全部勾选的进程，否则针对光标所在的进程
 func makeKillAction(v *ListView, force bool) ActionFunc {
 	return func(m View) (tea.Cmd, bool) {
+		focus := 0
+		if force {
+			focus = 1
+		}
+
+		// A. 批量处理：执行完打开结果页
+		if len(v.selected) > 0 {
+			procs := v.selectedProcesses()
+			choices := SignalChoices(func(op Operation) tea.Cmd { return StartBatch(v.state, op, procs) })
+			return ConfirmAction(v.state, fmt.Sprintf("Signal %d selected processes?", len(procs)), procs, focus, choices...), true
+		}
+
+		// B. 单个处理
+		if p := v.processList.SelectedItem(); p != nil {
+			pid := p.PID
+			choices := SignalChoices(func(op Operation) tea.Cmd { return v.opCmd(pid, op) })
+			return ConfirmAction(v.state, fmt.Sprintf("Signal %s (PID %d)?", p.Name, p.PID), []core.Process{*p}, focus, choices...), true
+		}
+		return nil, false
+	}
+}
+
+// makeOpAction 有勾选时批量执行 op，否则只针对光标所在的进程
+func makeOpAction(v *ListView, op Operation) ActionFunc {
+	return func(m View) (tea.Cmd, bool) {
+		if len(v.selected) > 0 {
+			return ConfirmBatch(v.state, op, v.selectedProcesses()), true
+		}
 		if p := v.processList.SelectedItem(); p != nil {
-			title := fmt.Sprintf("Sure to kill %s?", p.Name)
-			if force {
-				title = fmt.Sprintf("Sure to FORCE KILL %s?", p.Name)
+			cmd := v.opCmd(p.PID, op)
+			if op.Guarded {
+				cmd = GuardedAction(v.state, []core.Process{*p}, "", cmd)
 			}
-			return Push(NewConfirmDialog(title, v.killCmd(p.PID, force))), true
+			return cmd, true
 		}
 		return nil, false
 	}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/Microindole/quell/internal/core"
+	"github.com/Microindole/quell/internal/export"
 	"github.com/Microindole/quell/internal/tui/components" // 引用组件
 	"github.com/Microindole/quell/internal/version"
 	"github.com/charmbracelet/bubbles/key"
@@ -44,24 +45,39 @@ type ListView struct {
 	loading        bool
 	status         string
 	treeMode       bool
-	selectedPids   map[int32]bool
+	selected       Selection
+	saved          map[string]Selection // 本次会话中用 /select save 保存的选择
 	rawProcesses   []core.Process
+	memDetailAll   bool // 为全部进程采集 PSS/USS (默认只采集可见行)
+	header         *SystemHeader
+	width          int
+	height         int
 }
 
 func NewListView(state *SharedState, sortIdx int, treeMode bool) *ListView {
 	// 初始化组件
 	pl := components.NewProcessList(0, 0)
+	// 配置文件按下标保存排序方式 (sort_index)，新的排序只能追加在末尾
+	sorters := []Sorter{
+		StatusSorter{}, CPUSorter{}, MemSorter{}, PIDSorter{},
+		PSSSorter{}, USSSorter{}, SwapSorter{}, SharedMemSorter{}, IOSorter{},
+	}
 
 	v := &ListView{
 		state:          state,
 		processList:    pl,
 		registry:       &HandlerRegistry{},
-		sorters:        []Sorter{StatusSorter{}, CPUSorter{}, MemSorter{}, PIDSorter{}},
+		sorters:        sorters,
 		currentSortIdx: sortIdx,
 		treeMode:       treeMode,
 		loading:        true,
 		status:         "Scanning...",
-		selectedPids:   make(map[int32]bool),
+		selected:       make(Selection),
+		saved:          make(map[string]Selection),
+		header:         NewSystemHeader(false, state.Service.Now),
+	}
+	if v.currentSortIdx < 0 || v.currentSortIdx >= len(sorters) {
+		v.currentSortIdx = 0
 	}
 	if treeMode {
 		v.status = "Wait for scan (Tree View)..."
@@ -74,8 +90,13 @@ func (v *ListView) GetState() (int, bool) {
 	return v.currentSortIdx, v.treeMode
 }
 
+// SetHeaderCollapsed 恢复上次保存的摘要折叠状态
+func (v *ListView) SetHeaderCollapsed(collapsed bool) { v.header.Collapsed = collapsed }
+
+func (v *ListView) HeaderCollapsed() bool { return v.header.Collapsed }
+
 func (v *ListView) Init() tea.Cmd {
-	return v.refreshListCmd()
+	return tea.Batch(v.refreshListCmd(), fetchSystemStatsCmd(v.state.Service))
 }
 
 func (v *ListView) Update(msg tea.Msg) (View, tea.Cmd) {
@@ -84,16 +105,52 @@ func (v *ListView) Update(msg tea.Msg) (View, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		v.processList.SetSize(msg.Width-4, msg.Height-4)
+		v.width, v.height = msg.Width, msg.Height
+		v.resize()
 
 	case ClearSelectionMsg:
-		v.selectedPids = make(map[int32]bool)
+		v.selected = make(Selection)
 		cmd = v.updateListItems()
 		cmds = append(cmds, cmd)
 		return v, tea.Batch(cmds...)
 
-	case TickMsg:
-		return v, v.refreshListCmd()
+	case SelectMsg:
+		return v, v.applySelect(msg)
+
+	case BatchSelectionMsg:
+		procs := v.selectedProcesses()
+		if len(procs) == 0 {
+			return v, func() tea.Msg { return ProcessActionMsg{Err: fmt.Errorf("no processes selected")} }
+		}
+		state := v.state
+		if msg.Surface != "" {
+			state = v.state.As(msg.Surface)
+		}
+		if msg.Subtree {
+			procs = WithDescendants(state.Service, procs)
+		}
+		return v, ConfirmBatch(state, msg.Op, procs)
+
+	case BatchDoneMsg:
+		// 成功的进程取消勾选，失败的留着方便再次处理
+		for _, r := range msg.Results {
+			if !r.Failed() && v.selected.Has(r.Process) {
+				delete(v.selected, r.Process.PID)
+			}
+		}
+		cmd = v.updateListItems()
+		_, summary := v.Update(msg.Summary())
+		return v, tea.Batch(cmd, summary)
+
+	case TickMsg, ForceRefreshMsg:
+		return v, tea.Batch(v.refreshListCmd(), fetchSystemStatsCmd(v.state.Service))
+
+	case systemStatsMsg:
+		if msg.err == nil {
+			v.header.SetStats(msg.stats)
+			v.resize()
+		}
+		return v, nil
 
 	case []list.Item:
 		var rawProcs []core.Process
@@ -104,17 +161,28 @@ func (v *ListView) Update(msg tea.Msg) (View, tea.Cmd) {
 		}
 		v.loading = false
 		v.rawProcesses = rawProcs
+		// 已退出 (或 PID 被复用) 的进程从选择中去掉
+		v.selected.Prune(rawProcs)
+		for _, saved := range v.saved {
+			saved.Prune(rawProcs)
+		}
 		cmd = v.updateListItems()
 		return v, cmd
 
 	case ProcessActionMsg:
 		if msg.Err != nil {
-			v.status = fmt.Sprintf("Error: %v", msg.Err)
+			v.status = errorStatus(msg.Err)
 			return v, nil
 		}
 		v.status = fmt.Sprintf("%s successfully.", msg.Action)
+		if msg.Note != "" {
+			v.status += " Note: " + msg.Note
+		}
 		return v, v.delayedRefreshCmd()
 
+	case ExportMsg:
+		return v, v.exportCmd(msg)
+
 	case delayedRefreshMsg:
 		return v, v.refreshListCmd()
 
@@ -128,15 +196,18 @@ func (v *ListView) Update(msg tea.Msg) (View, tea.Cmd) {
 			}
 			if msg.String() == " " {
 				if p := v.processList.SelectedItem(); p != nil {
-					if v.selectedPids[p.PID] {
-						delete(v.selectedPids, p.PID)
-					} else {
-						v.selectedPids[p.PID] = true
-					}
+					v.selected.Toggle(*p)
 					return v, v.updateListItems()
 				}
 				return v, nil
 			}
+			// 过滤条件已经确定 (不在输入中) 时操作键照常可用，
+			// 这样可以在多次过滤之间逐步积累选择
+			if v.processList.Inner().FilterState() == list.FilterApplied {
+				if cmd, handled := v.registry.Handle(msg, v); handled {
+					return v, cmd
+				}
+			}
 
 			v.processList, cmd = v.processList.Update(msg)
 			return v, cmd
@@ -145,6 +216,10 @@ func (v *ListView) Update(msg tea.Msg) (View, tea.Cmd) {
 			return v, cmd
 		}
 	case SetFilterMsg:
+		if msg == "" {
+			v.processList.Inner().ResetFilter()
+			return v, v.updateListItems()
+		}
 		// 1. 设置输入框的值
 		v.processList.Inner().FilterInput.SetValue(string(msg))
 		v.processList.Inner().SetFilterState(list.Filtering)
@@ -169,8 +244,8 @@ func (v *ListView) updateListItems() tea.Cmd {
 	if v.treeMode {
 		treeProcs := BuildTree(v.rawProcesses)
 		finalProcs = treeProcs
-		if len(v.selectedPids) > 0 {
-			v.status = fmt.Sprintf("%d selected | Tree View", len(v.selectedPids))
+		if len(v.selected) > 0 {
+			v.status = fmt.Sprintf("%d selected | Tree View", len(v.selected))
 		} else {
 			v.status = fmt.Sprintf("Tree View: %d procs", len(v.rawProcesses))
 		}
@@ -188,14 +263,14 @@ func (v *ListView) updateListItems() tea.Cmd {
 		}
 
 		finalProcs = sortedRaw
-		if len(v.selectedPids) > 0 {
-			v.status = fmt.Sprintf("%d selected | Total: %d", len(v.selectedPids), len(v.rawProcesses))
+		if len(v.selected) > 0 {
+			v.status = fmt.Sprintf("%d selected | Total: %d", len(v.selected), len(v.rawProcesses))
 		} else {
 			v.status = fmt.Sprintf("Scanned %d processes.", len(v.rawProcesses))
 		}
 	}
 
-	cmd := v.processList.SetItems(finalProcs, v.selectedPids)
+	cmd := v.processList.SetItems(finalProcs, v.selected.PIDs())
 
 	if filterVal != "" {
 		v.processList.Inner().FilterInput.SetValue(filterVal)
@@ -219,7 +294,23 @@ func (v *ListView) View() string {
 		return lipgloss.Place(w, h, lipgloss.Center, lipgloss.Center, content)
 	}
 	// 🔥 使用组件渲染
-	return v.processList.View()
+	return v.renderHeader() + v.processList.View()
+}
+
+func (v *ListView) renderHeader() string {
+	return v.header.Render(v.width-4, core.CountTasks(v.rawProcesses))
+}
+
+// resize 根据摘要的高度重新分配列表的空间
+func (v *ListView) resize() {
+	if v.width == 0 {
+		return
+	}
+	h := v.height - 4
+	if header := v.renderHeader(); header != "" {
+		h -= lipgloss.Height(header)
+	}
+	v.processList.SetSize(v.width-4, h)
 }
 
 func (v *ListView) ShortHelp() []key.Binding { return v.registry.MakeHelp() }
@@ -232,11 +323,19 @@ func (v *ListView) registerActions() {
 }
 
 func (v *ListView) refreshListCmd() tea.Cmd {
+	// 在构造命令时确定采集范围，避免在后台 goroutine 中访问列表状态
+	var memPids map[int32]bool
+	if !v.needsFullMemDetail() {
+		memPids = v.visiblePids()
+	}
+
 	return func() tea.Msg {
 		procs, err := v.state.Service.GetProcesses()
 		if err != nil {
 			return nil
 		}
+		v.state.Service.FillMemoryDetail(procs, memPids)
+
 		// 为了保持 Init 接口兼容，这里我们先转成 []list.Item
 		// (或者你可以直接改 Service 返回类型处理，但为了最小改动，这里做个转换层)
 		items := make([]list.Item, len(procs))
@@ -247,12 +346,75 @@ func (v *ListView) refreshListCmd() tea.Cmd {
 	}
 }
 
-func (v *ListView) killCmd(pid int32, force bool) tea.Cmd {
+// needsFullMemDetail 当前排序依赖精确内存数据，或用户手动开启了全量采集
+func (v *ListView) needsFullMemDetail() bool {
+	if v.memDetailAll {
+		return true
+	}
+	ms, ok := v.sorters[v.currentSortIdx].(MemDetailSorter)
+	return ok && ms.NeedsMemDetail()
+}
+
+// visiblePids 返回当前页可见的进程
+func (v *ListView) visiblePids() map[int32]bool {
+	inner := v.processList.Inner()
+	items := inner.VisibleItems()
+	start, end := inner.Paginator.GetSliceBounds(len(items))
+
+	pids := make(map[int32]bool, end-start)
+	for _, item := range items[start:end] {
+		if pi, ok := item.(components.ProcessItem); ok {
+			pids[pi.GetProcess().PID] = true
+		}
+	}
+	return pids
+}
+
+func (v *ListView) opCmd(pid int32, op Operation) tea.Cmd {
+	return func() tea.Msg { return op.Run(v.state.Service, pid) }
+}
+
+// selectedProcesses 多选集合中仍然存在的进程
+func (v *ListView) selectedProcesses() []core.Process {
+	var procs []core.Process
+	for _, p := range v.rawProcesses {
+		if v.selected.Has(p) {
+			procs = append(procs, p)
+		}
+	}
+	return procs
+}
+
+// visibleProcesses 返回过滤后、按当前顺序排列的全部进程 (不止当前页)
+func (v *ListView) visibleProcesses() []core.Process {
+	var procs []core.Process
+	for _, item := range v.processList.Inner().VisibleItems() {
+		if pi, ok := item.(components.ProcessItem); ok {
+			p := pi.GetProcess()
+			p.TreePrefix = ""
+			procs = append(procs, p)
+		}
+	}
+	return procs
+}
+
+func (v *ListView) exportCmd(msg ExportMsg) tea.Cmd {
+	procs := v.visibleProcesses()
+	opts := msg.Options
+	opts.Tree = v.treeMode
+	if msg.Tree != nil {
+		opts.Tree = *msg.Tree
+	}
+	svc := v.state.Service
 	return func() tea.Msg {
-		return ProcessActionMsg{
-			Err:    v.state.Service.Kill(pid, force),
-			Action: "Killed",
+		// 列表默认只为可见行采集 PSS/USS，导出这两列时补齐全部行
+		if export.NeedsMemoryDetail(opts.Columns) {
+			svc.FillMemoryDetail(procs, nil)
+		}
+		if err := export.WriteFile(msg.Path, procs, opts); err != nil {
+			return ProcessActionMsg{Err: err}
 		}
+		return ProcessActionMsg{Action: fmt.Sprintf("Exported %d processes to %s", len(procs), msg.Path)}
 	}
 }
 
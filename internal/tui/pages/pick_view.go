@@ -0,0 +1,327 @@
+package pages
+
+import (
+	"fmt"
+	"syscall"
+
+	"github.com/Microindole/quell/internal/core"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PickEntry 是 PickView 中的一行：一个进程加上说明文字
+type PickEntry struct {
+	Process   core.Process
+	Detail    string
+	Selected  bool
+	Protected string // 受保护的原因，非空时不会被预先勾选
+}
+
+// PickLoader 在后台加载候选进程，note 会显示在状态栏 (例如跳过了多少进程)
+type PickLoader func() (entries []PickEntry, note string, err error)
+
+type pickItem struct {
+	entry PickEntry
+	idx   int
+}
+
+func (i pickItem) Title() string {
+	box := "[ ] "
+	if i.entry.Selected {
+		box = "[x] "
+	}
+	title := fmt.Sprintf("%s%s (PID %d, %s)", box, i.entry.Process.Name, i.entry.Process.PID, i.entry.Process.User)
+	if i.entry.Protected != "" {
+		title += " [protected]"
+	}
+	return title
+}
+func (i pickItem) Description() string {
+	if i.entry.Protected != "" {
+		return i.entry.Protected + " | " + i.entry.Detail
+	}
+	return i.entry.Detail
+}
+func (i pickItem) FilterValue() string {
+	return fmt.Sprintf("%s %d %s", i.entry.Process.Name, i.entry.Process.PID, i.entry.Detail)
+}
+
+// pickKey 按 PID + 启动时间识别进程，PID 被复用后不会继承原来的勾选
+type pickKey struct {
+	pid        int32
+	createTime int64
+}
+
+func keyOf(p core.Process) pickKey { return pickKey{p.PID, p.CreateTime} }
+
+type pickLoadedMsg struct {
+	entries []PickEntry
+	note    string
+	err     error
+}
+
+// PickView 通用的“可勾选进程列表”
+// 用于展示搜索结果 (/who-has 等)，勾选后可批量杀死或暂停
+type PickView struct {
+	state    *SharedState
+	registry *HandlerRegistry
+	list     list.Model
+	load     PickLoader
+	entries  []PickEntry
+	loading  bool
+	scanned  bool
+	status   string
+	note     string
+	signal   string // 非空时 Enter 对勾选的进程发送该信号 (/pkill)
+	// preselect 首次加载时勾选全部未受保护的进程；只用于 /pkill，
+	// 匹配结果本身就是用户要处理的集合。其余搜索结果默认一个都不勾选
+	preselect bool
+	// keepStatus 操作后的重新扫描保留操作结果提示，手动重新扫描 (r) 照常刷新状态栏
+	keepStatus bool
+}
+
+func NewPickView(state *SharedState, title string, load PickLoader) *PickView {
+	d := list.NewDefaultDelegate()
+	d.SetSpacing(0)
+
+	l := list.New([]list.Item{}, d, 0, 0)
+	l.Title = title
+	l.SetShowHelp(false)
+
+	v := &PickView{
+		state:    state,
+		registry: &HandlerRegistry{},
+		list:     l,
+		load:     load,
+		loading:  true,
+		status:   "Scanning...",
+	}
+	v.registerActions()
+	return v
+}
+
+func (v *PickView) Init() tea.Cmd {
+	return v.loadCmd()
+}
+
+func (v *PickView) Update(msg tea.Msg) (View, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		v.list.SetSize(msg.Width-4, msg.Height-4)
+		return v, nil
+
+	case pickLoadedMsg:
+		rescan, keepStatus := v.scanned, v.keepStatus
+		v.scanned, v.keepStatus = true, false
+		v.loading = false
+		if msg.err != nil {
+			v.status = fmt.Sprintf("Error: %v", msg.err)
+			return v, nil
+		}
+		// 重新扫描时按 PID + 启动时间沿用原来的勾选，新出现的进程不勾选，
+		// 用户取消勾选的进程不会在下一次操作时被重新选中
+		selected := make(map[pickKey]bool, len(v.entries))
+		for _, e := range v.entries {
+			selected[keyOf(e.Process)] = e.Selected
+		}
+		for i := range msg.entries {
+			e := &msg.entries[i]
+			if rescan {
+				e.Selected = selected[keyOf(e.Process)]
+			} else {
+				e.Selected = v.preselect && e.Protected == ""
+			}
+		}
+		v.entries = msg.entries
+		v.note = msg.note
+		if !keepStatus {
+			v.updateStatus()
+		}
+		return v, v.refreshItems()
+
+	case BatchDoneMsg:
+		return v.Update(msg.Summary())
+
+	case ProcessActionMsg:
+		// 操作后重新扫描，列表只保留仍然存活的进程
+		v.loading = true
+		v.keepStatus = true
+		cmd := v.loadCmd()
+		if msg.Err != nil {
+			v.status = errorStatus(msg.Err)
+		} else {
+			v.status = fmt.Sprintf("%s successfully.", msg.Action)
+		}
+		return v, cmd
+
+	case tea.KeyMsg:
+		if v.list.FilterState() != list.Filtering {
+			if cmd, handled := v.registry.Handle(msg, v); handled {
+				return v, cmd
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	v.list, cmd = v.list.Update(msg)
+	return v, cmd
+}
+
+func (v *PickView) registerActions() {
+	v.registry.Register(key.NewBinding(key.WithKeys("esc", "q"), key.WithHelp("esc", "back")),
+		func(m View) (tea.Cmd, bool) {
+			if v.list.FilterState() == list.FilterApplied {
+				v.list.ResetFilter()
+				return nil, true
+			}
+			return Pop(), true
+		})
+
+	v.registry.Register(key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "toggle")),
+		func(m View) (tea.Cmd, bool) {
+			item, ok := v.list.SelectedItem().(pickItem)
+			if !ok {
+				return nil, false
+			}
+			v.entries[item.idx].Selected = !v.entries[item.idx].Selected
+			v.updateStatus()
+			return v.refreshItems(), true
+		})
+
+	// a: 全选 / 全不选；与预先勾选一样跳过受保护的进程，它们只能用空格逐个勾选
+	v.registry.Register(key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "all/none")),
+		func(m View) (tea.Cmd, bool) {
+			all := false
+			for _, e := range v.entries {
+				if e.Protected == "" && !e.Selected {
+					all = true
+					break
+				}
+			}
+			for i := range v.entries {
+				v.entries[i].Selected = all && v.entries[i].Protected == ""
+			}
+			v.updateStatus()
+			return v.refreshItems(), true
+		})
+
+	v.registry.Register(key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "rescan")),
+		func(m View) (tea.Cmd, bool) {
+			v.loading = true
+			v.status = "Scanning..."
+			return v.loadCmd(), true
+		})
+
+	v.registry.Register(key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "kill selected")),
+		v.batchAction(OpTerminate))
+	v.registry.Register(key.NewBinding(key.WithKeys("X"), key.WithHelp("X", "force kill selected")),
+		v.batchAction(OpForceKill))
+	v.registry.Register(key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "suspend selected")),
+		v.batchAction(OpSuspend))
+	v.registry.Register(key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "resume selected")),
+		v.batchAction(OpResume))
+}
+
+// SetSignal 让 Enter 对勾选的进程发送 sig，用于 /pkill 的预览；首次加载时预先勾选匹配的进程
+func (v *PickView) SetSignal(sig syscall.Signal) {
+	v.signal = core.SignalName(sig)
+	v.preselect = true
+	v.registry.Register(key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "send "+v.signal)),
+		v.batchAction(OpSignal(sig)))
+}
+
+// batchAction 对所有勾选的进程执行 op，执行完打开结果页
+// 需要保护检查的操作先弹出确认框，恢复运行只展示影响
+func (v *PickView) batchAction(op Operation) ActionFunc {
+	return func(m View) (tea.Cmd, bool) {
+		targets := v.selected()
+		if len(targets) == 0 {
+			v.status = "Nothing selected."
+			return nil, true
+		}
+
+		run := StartBatch(v.state, op, targets)
+		prompt := op.Prompt(len(targets))
+		if skipped := v.protectedSkipped(); skipped > 0 {
+			prompt += fmt.Sprintf(" (%d matched, %d skipped as protected)", len(v.entries), skipped)
+		}
+		choice := op.Choice("y", run)
+		if !op.Guarded {
+			impacts := make([]core.Impact, len(targets))
+			for i, p := range targets {
+				impacts[i] = core.Impact{Process: p, Descendants: len(v.state.Service.Descendants(p.PID))}
+			}
+			return Push(NewActionDialog(prompt, impacts, []Choice{choice, CancelChoice})), true
+		}
+		return ConfirmAction(v.state, prompt, targets, 0, choice, CancelChoice), true
+	}
+}
+
+// protectedSkipped 未勾选的受保护进程数
+func (v *PickView) protectedSkipped() int {
+	n := 0
+	for _, e := range v.entries {
+		if e.Protected != "" && !e.Selected {
+			n++
+		}
+	}
+	return n
+}
+
+func (v *PickView) selected() []core.Process {
+	var procs []core.Process
+	for _, e := range v.entries {
+		if e.Selected {
+			procs = append(procs, e.Process)
+		}
+	}
+	return procs
+}
+
+func (v *PickView) updateStatus() {
+	v.status = fmt.Sprintf("%d/%d selected", len(v.selected()), len(v.entries))
+	if skipped := v.protectedSkipped(); skipped > 0 {
+		v.status += fmt.Sprintf(", %d protected", skipped)
+	}
+	if v.signal != "" {
+		v.status += " | enter: send " + v.signal
+	}
+	if v.note != "" {
+		v.status += " | " + v.note
+	}
+}
+
+func (v *PickView) refreshItems() tea.Cmd {
+	items := make([]list.Item, len(v.entries))
+	for i, e := range v.entries {
+		items[i] = pickItem{entry: e, idx: i}
+	}
+	return v.list.SetItems(items)
+}
+
+func (v *PickView) loadCmd() tea.Cmd {
+	return func() tea.Msg {
+		entries, note, err := v.load()
+		for i := range entries {
+			if verdict := v.state.Service.Classify(entries[i].Process); verdict.Level != core.GuardNone {
+				entries[i].Protected = verdict.Reason()
+			}
+		}
+		return pickLoadedMsg{entries: entries, note: note, err: err}
+	}
+}
+
+func (v *PickView) View() string {
+	if v.loading && len(v.entries) == 0 {
+		return "\n" + loadingTextStyle.Render(v.status)
+	}
+	if len(v.entries) == 0 {
+		return "\n" + loadingTextStyle.Render("No matching processes.")
+	}
+	return v.list.View()
+}
+
+func (v *PickView) ShortHelp() []key.Binding { return v.registry.MakeHelp() }
+
+func (v *PickView) GetStatus() string { return v.status }
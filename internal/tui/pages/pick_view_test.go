@@ -0,0 +1,69 @@
+package pages
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Microindole/quell/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func pickEntries() []PickEntry {
+	return []PickEntry{
+		{Process: core.Process{PID: 10, Name: "worker", CreateTime: 1}},
+		{Process: core.Process{PID: 11, Name: "worker", CreateTime: 2}},
+		{Process: core.Process{PID: 1, Name: "systemd", CreateTime: 3}, Protected: "init process"},
+	}
+}
+
+func pressKey(v View, k string) View {
+	v, _ = v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+	return v
+}
+
+func TestPickViewStatusAfterRescan(t *testing.T) {
+	v := NewPickView(&SharedState{}, "test", nil)
+	v.Update(pickLoadedMsg{entries: pickEntries()})
+	if want := "0/3 selected, 1 protected"; v.GetStatus() != want {
+		t.Fatalf("status = %q, want %q", v.GetStatus(), want)
+	}
+
+	// 手动重新扫描：扫描完恢复勾选统计
+	pressKey(v, "r")
+	if v.GetStatus() != "Scanning..." {
+		t.Fatalf("status while scanning = %q", v.GetStatus())
+	}
+	v.Update(pickLoadedMsg{entries: pickEntries()})
+	if want := "0/3 selected, 1 protected"; v.GetStatus() != want {
+		t.Errorf("status after rescan = %q, want %q", v.GetStatus(), want)
+	}
+
+	// 操作后的重新扫描保留结果提示，之后的手动扫描照常刷新
+	v.Update(ProcessActionMsg{Err: errors.New("boom")})
+	v.Update(pickLoadedMsg{entries: pickEntries()})
+	if v.GetStatus() == "0/3 selected, 1 protected" {
+		t.Errorf("action result was overwritten by the rescan")
+	}
+	pressKey(v, "r")
+	v.Update(pickLoadedMsg{entries: pickEntries()})
+	if want := "0/3 selected, 1 protected"; v.GetStatus() != want {
+		t.Errorf("status after manual rescan = %q, want %q", v.GetStatus(), want)
+	}
+}
+
+func TestPickViewSelectAllSkipsProtected(t *testing.T) {
+	v := NewPickView(&SharedState{}, "test", nil)
+	v.Update(pickLoadedMsg{entries: pickEntries()})
+
+	pressKey(v, "a")
+	if got := v.selected(); len(got) != 2 || got[0].PID != 10 || got[1].PID != 11 {
+		t.Fatalf("select all = %v, want PIDs 10 and 11", got)
+	}
+	if want := "2/3 selected, 1 protected"; v.GetStatus() != want {
+		t.Errorf("status = %q, want %q", v.GetStatus(), want)
+	}
+	pressKey(v, "a")
+	if got := v.selected(); len(got) != 0 {
+		t.Errorf("select none = %v", got)
+	}
+}
@@ -0,0 +1,261 @@
+package pages
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Microindole/quell/internal/core"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	sliderFillStyle  = lipgloss.NewStyle().Foreground(borderColor)
+	sliderTrackStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#3A3A3A"))
+	sliderKnobStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#FAFAFA")).Bold(true)
+)
+
+const priorityDialogWidth = 64
+
+// ioChoices I/O 优先级从低到高排列，左右键在其中切换
+var ioChoices = func() []core.IOPriority {
+	choices := []core.IOPriority{{Class: core.IOClassNone}, {Class: core.IOClassIdle}}
+	for l := core.MaxIOLevel; l >= 0; l-- {
+		choices = append(choices, core.IOPriority{Class: core.IOClassBestEffort, Level: l})
+	}
+	for l := core.MaxIOLevel; l >= 0; l-- {
+		choices = append(choices, core.IOPriority{Class: core.IOClassRealtime, Level: l})
+	}
+	return choices
+}()
+
+func ioChoiceIndex(p core.IOPriority) int {
+	for i, c := range ioChoices {
+		if c == p {
+			return i
+		}
+	}
+	return 0
+}
+
+// PriorityDialog 调整 nice 值与 I/O 优先级的弹窗：nice 是 -20..19 的滑块，I/O 优先级左右切换
+// 初始值取第一个目标进程的当前值，只有改动过的一项才会执行；t 切换是否连同子孙进程
+type PriorityDialog struct {
+	state   *SharedState
+	targets []core.Process
+
+	row            int // 0: nice，1: I/O
+	nice, origNice int
+	io, origIO     int     // ioChoices 的下标
+	touched        [2]bool // 两行各自是否调整过
+	subtree        bool
+	descendants    int
+	minNice        int // 目标中最小的 nice，调到比它更小需要 CAP_SYS_NICE
+	width, height  int
+}
+
+func NewPriorityDialog(state *SharedState, targets []core.Process) *PriorityDialog {
+	d := &PriorityDialog{state: state, targets: targets}
+	first := targets[0]
+	d.nice, d.origNice = int(first.Nice), int(first.Nice)
+	d.io = ioChoiceIndex(first.IOPriority)
+	d.origIO = d.io
+	d.minNice = core.MaxNice
+	for _, p := range targets {
+		d.minNice = min(d.minNice, int(p.Nice))
+	}
+	d.descendants = len(WithDescendants(state.Service, targets)) - len(targets)
+	return d
+}
+
+func (d *PriorityDialog) Init() tea.Cmd { return nil }
+
+func (d *PriorityDialog) Update(msg tea.Msg) (View, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// 扣除外层 appStyle 的内边距
+		d.width = msg.Width - 4
+		d.height = msg.Height - 2
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return d, tea.Quit
+		case "esc", "q":
+			return d, Pop()
+		case "up", "k", "down", "j", "tab", "shift+tab":
+			d.row = 1 - d.row
+		case "left", "h":
+			d.adjust(-1)
+		case "right", "l":
+			d.adjust(1)
+		case "pgdown", "H":
+			d.adjust(-5)
+		case "pgup", "L":
+			d.adjust(5)
+		case "0":
+			// 恢复默认：nice 0，I/O 跟随 nice
+			d.touched[d.row] = true
+			if d.row == 0 {
+				d.nice = 0
+			} else {
+				d.io = 0
+			}
+		case "t":
+			d.subtree = !d.subtree
+		case "enter", "y":
+			return d, d.apply()
+		}
+	}
+	return d, nil
+}
+
+// adjust 在当前行上移动；nice 滑块向右是更大的 nice (更低的优先级)
+func (d *PriorityDialog) adjust(delta int) {
+	d.touched[d.row] = true
+	if d.row == 0 {
+		d.nice = max(core.MinNice, min(core.MaxNice, d.nice+delta))
+		return
+	}
+	d.io = max(0, min(len(ioChoices)-1, d.io+delta))
+}
+
+// operation 由改动过的项组成的操作，都没改时 ok 为 false
+// 单个目标时调回原值算没有改动；多个目标的当前值可能各不相同，调整过就按滑块的值设置
+func (d *PriorityDialog) operation() (op Operation, ok bool) {
+	multi := len(d.targets) > 1 || d.subtree
+	niceChanged := d.touched[0] && (multi || d.nice != d.origNice)
+	ioChanged := d.touched[1] && (multi || d.io != d.origIO)
+	switch {
+	case niceChanged && ioChanged:
+		return OpRenice(d.nice).Then(OpIONice(ioChoices[d.io])), true
+	case niceChanged:
+		return OpRenice(d.nice), true
+	case ioChanged:
+		return OpIONice(ioChoices[d.io]), true
+	}
+	return Operation{}, false
+}
+
+func (d *PriorityDialog) apply() tea.Cmd {
+	op, ok := d.operation()
+	if !ok {
+		return Pop()
+	}
+	targets := d.targets
+	if d.subtree {
+		targets = WithDescendants(d.state.Service, targets)
+	}
+	// 先回到上一页，结果 (或批量结果页) 才会交给正确的页面
+	if len(targets) == 1 {
+		svc, pid := d.state.Service, targets[0].PID
+		return tea.Sequence(Pop(), func() tea.Msg { return op.Run(svc, pid) })
+	}
+	return tea.Sequence(Pop(), StartBatch(d.state, op, targets))
+}
+
+func (d *PriorityDialog) View() string {
+	w := priorityDialogWidth
+	if d.width > 0 && d.width-actionDialogMargin < w {
+		w = max(40, d.width-actionDialogMargin)
+	}
+	inner := w - 2
+
+	title := fmt.Sprintf("Priority of %s (PID %d)", d.targets[0].Name, d.targets[0].PID)
+	if len(d.targets) > 1 {
+		title = fmt.Sprintf("Priority of %d selected processes", len(d.targets))
+	}
+	first := d.targets[0]
+	current := fmt.Sprintf("now: nice %s · pri %s · I/O %s", first.NiceString(), first.PriorityString(), first.IOPriority)
+	if len(d.targets) > 1 {
+		current = "now (first): " + strings.TrimPrefix(current, "now: ")
+	}
+
+	lines := []string{
+		headerStyle.Width(inner).Render("PRIORITY"),
+		"",
+		actionTitleStyle.Width(inner).Render(truncate(title, inner)),
+		tabHintStyle.Render(truncate(current, inner)),
+		"",
+		d.rowLabel(0, "nice") + d.niceSlider(inner-16) + fmt.Sprintf(" %3d", d.nice),
+		d.rowLabel(1, "I/O") + d.ioPicker(),
+		"",
+	}
+
+	tree := fmt.Sprintf("off (%d descendants)", d.descendants)
+	if d.subtree {
+		tree = fmt.Sprintf("on (+%d descendants)", d.descendants)
+	}
+	lines = append(lines, truncate("[t] include children: "+tree, inner))
+
+	if op, ok := d.operation(); ok {
+		n := len(d.targets)
+		if d.subtree {
+			n += d.descendants
+		}
+		lines = append(lines, actionTitleStyle.Render(truncate(fmt.Sprintf("enter: %s on %d processes", op.Label, n), inner)))
+	} else {
+		lines = append(lines, tabHintStyle.Render("no change"))
+	}
+	if d.nice < d.minNice && !d.state.IsAdmin {
+		lines = append(lines, actionWarnStyle.Render(truncate("note: raising priority (lower nice) needs root or CAP_SYS_NICE", inner)))
+	}
+	if ioChoices[d.io].Class == core.IOClassRealtime && d.touched[1] && !d.state.IsAdmin {
+		lines = append(lines, actionWarnStyle.Render(truncate("note: the realtime I/O class needs root or CAP_SYS_NICE", inner)))
+	}
+	lines = append(lines, "", tabHintStyle.Render(truncate("↑↓ field · ←→ adjust (H/L ±5) · 0 reset · enter apply · esc", inner)))
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Padding(0, 1).
+		Width(w).
+		Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+
+	if d.width > 0 && d.height > 0 {
+		// 预留底部状态栏的高度
+		return lipgloss.Place(d.width, max(0, d.height-2), lipgloss.Center, lipgloss.Center, box)
+	}
+	return "\n\n" + lipgloss.PlaceHorizontal(80, lipgloss.Center, box)
+}
+
+func (d *PriorityDialog) rowLabel(row int, label string) string {
+	cursor := "  "
+	style := actionHeadStyle
+	if d.row == row {
+		cursor = "> "
+		style = actionTitleStyle
+	}
+	return cursor + style.Render(fmt.Sprintf("%-5s", label))
+}
+
+// niceSlider -20 ━━━━━●──── 19，左侧 (高优先级) 到滑块的部分着色
+func (d *PriorityDialog) niceSlider(width int) string {
+	track := max(10, width-8)
+	steps := core.MaxNice - core.MinNice
+	pos := (d.nice - core.MinNice) * (track - 1) / steps
+	bar := sliderFillStyle.Render(strings.Repeat("━", pos)) +
+		sliderKnobStyle.Render("●") +
+		sliderTrackStyle.Render(strings.Repeat("─", track-1-pos))
+	return fmt.Sprintf("%3d %s %-3d", core.MinNice, bar, core.MaxNice)
+}
+
+// ioPicker ‹ be/4 › 以及对类别的说明
+func (d *PriorityDialog) ioPicker() string {
+	p := ioChoices[d.io]
+	var desc string
+	switch p.Class {
+	case core.IOClassNone:
+		desc = "default, follows nice"
+	case core.IOClassIdle:
+		desc = "only when the disk is idle"
+	case core.IOClassBestEffort:
+		desc = "best effort, 0 = highest"
+	case core.IOClassRealtime:
+		desc = "realtime, may starve others"
+	}
+	return fmt.Sprintf("‹ %-5s › ", p) + tabHintStyle.Render(desc)
+}
+
+func (d *PriorityDialog) ShortHelp() []key.Binding { return nil }
@@ -1,11 +1,30 @@
 package pages
 
-import tea "github.com/charmbracelet/bubbletea"
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
 
 // CommandFunc 定义命令函数的标准签名
 // args: 参数列表 (例如 ["-f"]), state: 全局状态
+// 命令既可能从命令输入框执行，也可能经控制 socket 执行，因此不负责关闭输入框
 type CommandFunc func(args []string, state *SharedState) (View, tea.Cmd)
 
 // CommandRegistry 全局命令注册表
 // 初始化为空，等待 main/model 层注入具体实现
 var CommandRegistry = make(map[string]CommandFunc)
+
+// LookupCommand 把一行命令 (例如 "/filter node") 拆成命令名、处理函数和参数
+// 开头的 "/" 可以省略；命令不存在时 fn 为 nil
+func LookupCommand(line string) (name string, fn CommandFunc, args []string) {
+	parts := strings.Fields(line)
+	if len(parts) == 0 {
+		return "", nil, nil
+	}
+	name = parts[0]
+	if !strings.HasPrefix(name, "/") {
+		name = "/" + name
+	}
+	return name, CommandRegistry[name], parts[1:]
+}
@@ -0,0 +1,265 @@
+package pages
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Microindole/quell/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Selection 多选集合：PID → 启动时间
+// 按身份识别进程，进程退出或 PID 被复用后会在下次刷新时被清理掉
+type Selection map[int32]int64
+
+func (s Selection) Has(p core.Process) bool {
+	ct, ok := s[p.PID]
+	return ok && ct == p.CreateTime
+}
+
+func (s Selection) Add(p core.Process) { s[p.PID] = p.CreateTime }
+
+// Toggle 勾选或取消勾选
+func (s Selection) Toggle(p core.Process) {
+	if s.Has(p) {
+		delete(s, p.PID)
+	} else {
+		s.Add(p)
+	}
+}
+
+// Prune 去掉已经不在 procs 中的进程 (按 PID + 启动时间)，返回去掉的个数
+func (s Selection) Prune(procs []core.Process) int {
+	alive := make(map[int32]int64, len(procs))
+	for _, p := range procs {
+		alive[p.PID] = p.CreateTime
+	}
+	removed := 0
+	for pid, ct := range s {
+		if act, ok := alive[pid]; !ok || act != ct {
+			delete(s, pid)
+			removed++
+		}
+	}
+	return removed
+}
+
+// PIDs 供列表组件显示勾选框
+func (s Selection) PIDs() map[int32]bool {
+	pids := make(map[int32]bool, len(s))
+	for pid := range s {
+		pids[pid] = true
+	}
+	return pids
+}
+
+func (s Selection) Clone() Selection {
+	c := make(Selection, len(s))
+	for pid, ct := range s {
+		c[pid] = ct
+	}
+	return c
+}
+
+// SelectOp /select 的子命令
+type SelectOp int
+
+const (
+	SelectPIDs     SelectOp = iota // 加入指定 PID
+	SelectQuery                    // 加入满足查询条件的全部进程
+	SelectVisible                  // 加入当前 (过滤后) 列表中的全部进程
+	SelectInvert                   // 反选当前列表中的进程
+	SelectSubtree                  // 加入进程及其全部子孙进程
+	SelectSiblings                 // 加入与进程同名、同一父进程的兄弟进程
+	SelectSave                     // 把当前选择保存为 Name
+	SelectRecall                   // 把保存的选择并入当前选择
+	SelectDrop                     // 删除保存的选择
+	SelectSaved                    // 列出保存的选择
+)
+
+// SelectMsg 修改 ListView 的多选集合
+// SelectSubtree / SelectSiblings 的 PIDs 为空时使用光标所在的进程
+type SelectMsg struct {
+	Op    SelectOp
+	PIDs  []int32
+	Query core.ProcessQuery
+	Name  string
+}
+
+// applySelect 执行 SelectMsg，结果以 ProcessActionMsg 显示在状态栏
+func (v *ListView) applySelect(msg SelectMsg) tea.Cmd {
+	before := len(v.selected)
+	var err error
+	switch msg.Op {
+	case SelectPIDs:
+		err = v.selectPIDs(msg.PIDs)
+	case SelectQuery:
+		n := 0
+		for _, p := range v.rawProcesses {
+			if msg.Query.Match(p) {
+				v.selected.Add(p)
+				n++
+			}
+		}
+		if n == 0 {
+			err = fmt.Errorf("no process matches the query")
+		}
+	case SelectVisible:
+		for _, p := range v.visibleProcesses() {
+			v.selected.Add(p)
+		}
+	case SelectInvert:
+		for _, p := range v.visibleProcesses() {
+			v.selected.Toggle(p)
+		}
+	case SelectSubtree, SelectSiblings:
+		var roots []core.Process
+		roots, err = v.selectRoots(msg.PIDs)
+		for _, root := range roots {
+			if msg.Op == SelectSubtree {
+				v.selectSubtree(root)
+			} else {
+				v.selectSiblings(root)
+			}
+		}
+	case SelectSave:
+		switch {
+		case msg.Name == "":
+			err = fmt.Errorf("usage: /select save <name>")
+		case len(v.selected) == 0:
+			err = fmt.Errorf("nothing selected to save")
+		default:
+			v.saved[msg.Name] = v.selected.Clone()
+			return v.selectResult(fmt.Sprintf("Saved selection %q (%d processes)", msg.Name, len(v.selected)), nil)
+		}
+	case SelectRecall:
+		saved, ok := v.saved[msg.Name]
+		if !ok {
+			err = fmt.Errorf("no saved selection %q (%s)", msg.Name, v.savedSummary())
+			break
+		}
+		for pid, ct := range saved {
+			v.selected[pid] = ct
+		}
+	case SelectDrop:
+		if _, ok := v.saved[msg.Name]; !ok {
+			err = fmt.Errorf("no saved selection %q", msg.Name)
+			break
+		}
+		delete(v.saved, msg.Name)
+		return v.selectResult(fmt.Sprintf("Dropped selection %q", msg.Name), nil)
+	case SelectSaved:
+		return v.selectResult("Saved selections: "+v.savedSummary(), nil)
+	}
+
+	action := fmt.Sprintf("Selected %d processes", len(v.selected))
+	if added := len(v.selected) - before; added > 0 && before > 0 {
+		action += fmt.Sprintf(" (+%d)", added)
+	}
+	return v.selectResult(action, err)
+}
+
+func (v *ListView) selectResult(action string, err error) tea.Cmd {
+	result := ProcessActionMsg{Action: action, Err: err}
+	return tea.Batch(v.updateListItems(), func() tea.Msg { return result })
+}
+
+// selectPIDs 选中给定的进程，不存在的 PID 会报告出来，其余照常选中
+func (v *ListView) selectPIDs(pids []int32) error {
+	known := make(map[int32]core.Process, len(v.rawProcesses))
+	for _, p := range v.rawProcesses {
+		known[p.PID] = p
+	}
+	var missing []string
+	for _, pid := range pids {
+		p, ok := known[pid]
+		if !ok {
+			missing = append(missing, strconv.Itoa(int(pid)))
+			continue
+		}
+		v.selected.Add(p)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("no such process: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// selectRoots 子树、兄弟进程的起点：给定的 PID，或光标所在的进程
+func (v *ListView) selectRoots(pids []int32) ([]core.Process, error) {
+	if len(pids) == 0 {
+		if p := v.processList.SelectedItem(); p != nil {
+			return []core.Process{*p}, nil
+		}
+		return nil, fmt.Errorf("no process under the cursor")
+	}
+	var roots []core.Process
+	var missing []string
+	for _, pid := range pids {
+		found := false
+		for _, p := range v.rawProcesses {
+			if p.PID == pid {
+				roots = append(roots, p)
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, strconv.Itoa(int(pid)))
+		}
+	}
+	if len(missing) > 0 {
+		return roots, fmt.Errorf("no such process: %s", strings.Join(missing, ", "))
+	}
+	return roots, nil
+}
+
+func (v *ListView) selectSubtree(root core.Process) {
+	children := make(map[int32][]core.Process)
+	for _, p := range v.rawProcesses {
+		if p.PPID != p.PID {
+			children[p.PPID] = append(children[p.PPID], p)
+		}
+	}
+	// 已经勾选的子进程也要继续向下找，否则它的子孙会被漏掉；seen 只用来防止环
+	v.selected.Add(root)
+	seen := map[int32]bool{root.PID: true}
+	queue := []int32{root.PID}
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = queue[1:]
+		for _, c := range children[pid] {
+			v.selected.Add(c)
+			if !seen[c.PID] {
+				seen[c.PID] = true
+				queue = append(queue, c.PID)
+			}
+		}
+	}
+}
+
+func (v *ListView) selectSiblings(root core.Process) {
+	for _, p := range v.rawProcesses {
+		if p.PPID == root.PPID && p.Name == root.Name {
+			v.selected.Add(p)
+		}
+	}
+}
+
+// savedSummary "build (3), web (12)"
+func (v *ListView) savedSummary() string {
+	if len(v.saved) == 0 {
+		return "none saved"
+	}
+	names := make([]string, 0, len(v.saved))
+	for name := range v.saved {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		names[i] = fmt.Sprintf("%s (%d)", name, len(v.saved[name]))
+	}
+	return strings.Join(names, ", ")
+}
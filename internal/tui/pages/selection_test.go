@@ -0,0 +1,49 @@
+package pages
+
+import (
+	"testing"
+
+	"github.com/Microindole/quell/internal/core"
+)
+
+func TestSelectSubtree(t *testing.T) {
+	// 1 ─ 2 ─ 3 ─ 4
+	//       └ 5
+	// 6 (不相关)
+	procs := []core.Process{
+		{PID: 1, PPID: 0, CreateTime: 100},
+		{PID: 2, PPID: 1, CreateTime: 200},
+		{PID: 3, PPID: 2, CreateTime: 300},
+		{PID: 4, PPID: 3, CreateTime: 400},
+		{PID: 5, PPID: 2, CreateTime: 500},
+		{PID: 6, PPID: 0, CreateTime: 600},
+	}
+	tests := []struct {
+		name     string
+		already  []int // 事先勾选的下标
+		root     int
+		wantPIDs []int32
+	}{
+		{"whole tree", nil, 0, []int32{1, 2, 3, 4, 5}},
+		{"middle node already selected", []int{1}, 0, []int32{1, 2, 3, 4, 5}},
+		{"grandchild already selected", []int{2}, 1, []int32{2, 3, 4, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &ListView{rawProcesses: procs, selected: make(Selection)}
+			for _, i := range tt.already {
+				v.selected.Add(procs[i])
+			}
+			v.selectSubtree(procs[tt.root])
+
+			if len(v.selected) != len(tt.wantPIDs) {
+				t.Errorf("selected %v, want PIDs %v", v.selected, tt.wantPIDs)
+			}
+			for _, pid := range tt.wantPIDs {
+				if _, ok := v.selected[pid]; !ok {
+					t.Errorf("PID %d not selected", pid)
+				}
+			}
+		})
+	}
+}
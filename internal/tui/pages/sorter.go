@@ -40,3 +40,44 @@ type CPUSorter struct{}
 
 func (s CPUSorter) Name() string                  { return "CPU ⬇" }
 func (s CPUSorter) Less(p1, p2 core.Process) bool { return p1.CpuPercent > p2.CpuPercent }
+
+type IOSorter struct{}
+
+func (s IOSorter) Name() string { return "I/O ⬇" }
+func (s IOSorter) Less(p1, p2 core.Process) bool {
+	// 无法读取 I/O 的进程排在最后
+	if p1.HasIO != p2.HasIO {
+		return p1.HasIO
+	}
+	return p1.IORate() > p2.IORate()
+}
+
+// MemDetailSorter 由依赖精确内存数据的排序方式实现
+// 选中这类排序时，ListView 会为全部进程采集 smaps_rollup，而不只是可见行
+type MemDetailSorter interface {
+	NeedsMemDetail() bool
+}
+
+type PSSSorter struct{}
+
+func (s PSSSorter) Name() string                  { return "PSS ⬇" }
+func (s PSSSorter) Less(p1, p2 core.Process) bool { return p1.PSS > p2.PSS }
+func (s PSSSorter) NeedsMemDetail() bool          { return true }
+
+type USSSorter struct{}
+
+func (s USSSorter) Name() string                  { return "USS ⬇" }
+func (s USSSorter) Less(p1, p2 core.Process) bool { return p1.USS > p2.USS }
+func (s USSSorter) NeedsMemDetail() bool          { return true }
+
+type SwapSorter struct{}
+
+func (s SwapSorter) Name() string                  { return "Swap ⬇" }
+func (s SwapSorter) Less(p1, p2 core.Process) bool { return p1.Swap > p2.Swap }
+func (s SwapSorter) NeedsMemDetail() bool          { return true }
+
+type SharedMemSorter struct{}
+
+func (s SharedMemSorter) Name() string                  { return "Shared ⬇" }
+func (s SharedMemSorter) Less(p1, p2 core.Process) bool { return p1.SharedMem > p2.SharedMem }
+func (s SharedMemSorter) NeedsMemDetail() bool          { return true }
@@ -0,0 +1,173 @@
+package pages
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Microindole/quell/internal/core"
+	"github.com/Microindole/quell/internal/tui/components"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	headerTextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#A0A0A0"))
+	headerKeyStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4")).Bold(true)
+	headerBoxStyle  = lipgloss.NewStyle().MarginBottom(1)
+)
+
+// 每列最多显示的核心数，核心多时横向扩展
+const maxCoreRows = 4
+
+type systemStatsMsg struct {
+	stats core.SystemStats
+	err   error
+}
+
+// SystemHeader 列表页顶部的整机摘要：负载、运行时间、每核 CPU、内存/Swap 以及任务统计
+type SystemHeader struct {
+	stats     core.SystemStats
+	hasStats  bool
+	Collapsed bool
+	ShowChart bool // 展开时附带整机 CPU/内存历史图表
+	Zoom      int  // chartWindows 下标
+
+	cpuHistory *components.TimeSeries
+	memHistory *components.TimeSeries
+
+	cpuGauge  *components.Gauge
+	memGauge  *components.Gauge
+	swapGauge *components.Gauge
+
+	clock func() time.Time // 回放时是录制时间
+}
+
+func NewSystemHeader(collapsed bool, clock func() time.Time) *SystemHeader {
+	return &SystemHeader{
+		Collapsed:  collapsed,
+		clock:      clock,
+		cpuHistory: components.NewTimeSeries(historyRetention),
+		memHistory: components.NewTimeSeries(historyRetention),
+		cpuGauge:   components.NewGauge(lipgloss.NewStyle().Foreground(cpuColor)),
+		memGauge:   components.NewGauge(lipgloss.NewStyle().Foreground(memColor)),
+		swapGauge:  components.NewGauge(lipgloss.NewStyle().Foreground(ioColor)),
+	}
+}
+
+func (h *SystemHeader) SetStats(s core.SystemStats) {
+	h.stats = s
+	h.hasStats = true
+	now := h.clock()
+	h.cpuHistory.Add(now, avgCPU(s.PerCPU))
+	h.memHistory.Add(now, ratio(s.MemUsed, s.MemTotal)*100)
+}
+
+func fetchSystemStatsCmd(svc *core.Service) tea.Cmd {
+	return func() tea.Msg {
+		stats, err := svc.GetSystemStats()
+		return systemStatsMsg{stats: stats, err: err}
+	}
+}
+
+// Render 渲染摘要；width 为可用宽度
+func (h *SystemHeader) Render(width int, tasks core.TaskCounts) string {
+	if !h.hasStats {
+		return ""
+	}
+	s := h.stats
+
+	if h.Collapsed {
+		line := fmt.Sprintf("Load %.2f %.2f %.2f | CPU %.0f%% | Mem %.0f%% | Swap %.0f%% | Tasks %d (%d R, %d T, %d Z)",
+			s.Load1, s.Load5, s.Load15, avgCPU(s.PerCPU), ratio(s.MemUsed, s.MemTotal)*100,
+			ratio(s.SwapUsed, s.SwapTotal)*100, tasks.Total, tasks.Running, tasks.Stopped, tasks.Zombie)
+		return headerBoxStyle.Render(headerTextStyle.Render(line))
+	}
+
+	info := fmt.Sprintf("%s %.2f %.2f %.2f  %s %s  %s %d total, %d running, %d sleeping, %d stopped, %d zombie",
+		headerKeyStyle.Render("Load:"), s.Load1, s.Load5, s.Load15,
+		headerKeyStyle.Render("Uptime:"), formatUptime(s.Uptime),
+		headerKeyStyle.Render("Tasks:"), tasks.Total, tasks.Running, tasks.Sleeping, tasks.Stopped, tasks.Zombie)
+
+	lines := []string{headerTextStyle.Render(info)}
+	lines = append(lines, h.renderCores(width)...)
+
+	half := width/2 - 1
+	mem := h.memGauge.Render("Mem ", ratio(s.MemUsed, s.MemTotal),
+		fmt.Sprintf("%s/%s", core.FormatBytes(s.MemUsed), core.FormatBytes(s.MemTotal)), half)
+	swap := h.swapGauge.Render("Swap", ratio(s.SwapUsed, s.SwapTotal),
+		fmt.Sprintf("%s/%s", core.FormatBytes(s.SwapUsed), core.FormatBytes(s.SwapTotal)), half)
+	lines = append(lines, mem+"  "+swap)
+	if h.ShowChart {
+		lines = append(lines, h.renderChart(width))
+	}
+
+	return headerBoxStyle.Render(strings.Join(lines, "\n"))
+}
+
+// renderChart 整机 CPU 与内存占用率的历史，固定 0-100% 刻度
+func (h *SystemHeader) renderChart(width int) string {
+	c := &components.Chart{
+		Width:   width - 8,
+		Height:  3,
+		Window:  chartWindows[h.Zoom],
+		Primary: components.Axis{Min: 0, Max: 100, Format: func(v float64) string { return fmt.Sprintf("%.0f%%", v) }},
+	}
+	series := []components.Series{
+		{Name: "CPU", Points: h.cpuHistory.Points, Color: cpuColor},
+		{Name: "Mem", Points: h.memHistory.Points, Color: memColor},
+	}
+	legend := fmt.Sprintf("%s  %s", components.Legend(series...),
+		headerTextStyle.Render(fmt.Sprintf("(last %s, z to zoom)", components.FormatWindow(chartWindows[h.Zoom]))))
+	return legend + "\n" + c.Render(h.clock(), series...)
+}
+
+// renderCores 把每个核心渲染成一根进度条，按列排列
+func (h *SystemHeader) renderCores(width int) []string {
+	n := len(h.stats.PerCPU)
+	if n == 0 {
+		return nil
+	}
+	cols := (n + maxCoreRows - 1) / maxCoreRows
+	rows := (n + cols - 1) / cols
+	colWidth := width/cols - 1
+
+	lines := make([]string, rows)
+	for i, pct := range h.stats.PerCPU {
+		row, col := i%rows, i/rows
+		bar := h.cpuGauge.Render(fmt.Sprintf("%3d", i), pct/100, fmt.Sprintf("%.0f%%", pct), colWidth)
+		if col > 0 {
+			lines[row] += " "
+		}
+		lines[row] += bar
+	}
+	return lines
+}
+
+func ratio(used, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(used) / float64(total)
+}
+
+func avgCPU(perCPU []float64) float64 {
+	if len(perCPU) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range perCPU {
+		sum += v
+	}
+	return sum / float64(len(perCPU))
+}
+
+func formatUptime(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	mins := int(d.Minutes()) % 60
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh %dm", days, hours, mins)
+	}
+	return fmt.Sprintf("%dh %dm", hours, mins)
+}
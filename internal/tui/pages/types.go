@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/Microindole/quell/internal/core"
+	"github.com/Microindole/quell/internal/export"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -20,6 +21,23 @@ type View interface {
 type SharedState struct {
 	Service *core.Service
 	IsAdmin bool
+	Replay  ReplayControl     // 回放录制文件时非空
+	Batch   core.BatchOptions // 批量操作的并发数与执行顺序
+}
+
+// As 返回 Service 带有来源标记的副本，命令模式和控制 socket 用它区分审计日志中的操作来源
+func (s *SharedState) As(surface core.Surface) *SharedState {
+	c := *s
+	c.Service = s.Service.As(surface)
+	return &c
+}
+
+// ReplayControl 回放的播放控制，由 snapshot.ReplayProvider 实现
+type ReplayControl interface {
+	TogglePause()
+	Seek(d time.Duration)
+	ScaleSpeed(factor float64)
+	Status() string
 }
 
 type PushViewMsg struct{ View View }
@@ -29,11 +47,29 @@ type ReplaceViewMsg struct{ View View }
 type ProcessActionMsg struct {
 	Err    error
 	Action string // 例如: "Killed", "Suspended", "Resumed"
+	Note   string // 成功但需要留意的情况，例如亲和性与 cgroup cpuset 不一致
 }
 
 type ForceRefreshMsg struct{}
 type SetFilterMsg string
 
+// BatchSelectionMsg 让 ListView 对勾选的全部进程执行 Op (不带 PID 的 /kill、/signal 等)
+// Subtree 为 true 时连同它们的子孙进程 (/renice -t)；Surface 是发起命令的界面，
+// 确认与执行都以它的名义进行，审计日志记为 command / ctl 而不是列表自己的 key
+type BatchSelectionMsg struct {
+	Op      Operation
+	Subtree bool
+	Surface core.Surface
+}
+
+// ExportMsg 请求 ListView 把当前 (过滤、排序后的) 列表导出到文件
+// Tree 为 nil 时跟随当前是否处于树状视图
+type ExportMsg struct {
+	Path    string
+	Options export.Options
+	Tree    *bool
+}
+
 func Push(v View) tea.Cmd {
 	return func() tea.Msg { return PushViewMsg{View: v} }
 }
@@ -52,3 +88,8 @@ func TickCmd() tea.Cmd {
 		return TickMsg(t)
 	})
 }
+
+// StatusReporter 由需要在底部状态栏展示信息的页面实现
+type StatusReporter interface {
+	GetStatus() string
+}